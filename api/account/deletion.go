@@ -0,0 +1,157 @@
+// account/deletion.go
+package account
+
+import (
+	"database/sql"
+	"guny-world-backend/api/avatar"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/oauth"
+	"guny-world-backend/api/session"
+	"log"
+	"os"
+	"time"
+)
+
+// 탈퇴 후 익명화 전까지 기본 유예 기간
+const defaultDeletionGrace = time.Hour * 24 * 30
+
+// 익명화 작업 실행 간격
+const purgeInterval = time.Hour
+
+// 익명화된 계정의 닉네임
+const deletedNickname = "탈퇴한 사용자"
+
+// 탈퇴 유예 기간 (ACCOUNT_DELETION_GRACE, 예: 720h)
+func DeletionGrace() time.Duration {
+	if grace, err := time.ParseDuration(os.Getenv("ACCOUNT_DELETION_GRACE")); err == nil && grace >= 0 {
+		return grace
+	}
+	return defaultDeletionGrace
+}
+
+// 탈퇴 요청 (유예 기간 동안은 다시 로그인하면 복구) 후 모든 토큰 폐기
+func Delete(accountId string) (purgeAt time.Time, err error) {
+	now := time.Now()
+	_, err = database.DB.Exec("UPDATE accounts SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", now, accountId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err = session.RevokeAllForUser(accountId); err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(DeletionGrace()), nil
+}
+
+// 유예 기간 중인 탈퇴 취소 (탈퇴 요청 중이었으면 true)
+func Restore(accountId string) (bool, error) {
+	result, err := database.DB.Exec("UPDATE accounts SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL AND purged_at IS NULL", accountId)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// 유예 기간이 지난 계정을 주기적으로 익명화 (서버 시작 시 호출)
+func StartPurgeJob() {
+	go func() {
+		for {
+			if err := Purge(time.Now().Add(-DeletionGrace())); err != nil {
+				log.Println("탈퇴 계정 익명화 실패: ", err)
+			}
+			time.Sleep(purgeInterval)
+		}
+	}()
+}
+
+// cutoff 이전에 탈퇴 요청한 계정 익명화
+// 계정 행은 다른 기록(관리자 작업 기록 등)이 가리킬 수 있으므로 남기고 개인 정보만 지움
+func Purge(cutoff time.Time) error {
+	var ids []string
+	err := database.DB.Select(&ids, "SELECT id FROM accounts WHERE deleted_at < ? AND purged_at IS NULL", cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := purgeAccount(id, cutoff); err != nil {
+			log.Println("탈퇴 계정 익명화 실패: ", id, err)
+		}
+	}
+	return nil
+}
+
+func purgeAccount(accountId string, cutoff time.Time) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 직접 올린 프로필 이미지
+	var avatarKey sql.NullString
+	if err = tx.Get(&avatarKey, "SELECT avatar_key FROM accounts WHERE id = ?", accountId); err != nil {
+		return err
+	}
+
+	// 그 사이 로그인해서 복구됐으면 건너뜀
+	result, err := tx.Exec(`UPDATE accounts SET email = NULL, password = NULL, nickname = ?, profile_image = NULL, avatar_key = NULL, name = NULL,
+		email_verified_at = NULL, suspended_at = NULL, suspended_until = NULL, suspension_reason = NULL,
+		purged_at = ? WHERE id = ? AND deleted_at < ? AND purged_at IS NULL`,
+		deletedNickname, time.Now(), accountId, cutoff)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return err
+	}
+
+	// 외부 로그인 제공자 연동 해제용 토큰 (복구되지 않은 것을 확인한 뒤, 연결 정보를 지우기 전에 조회)
+	providerTokens, err := oauth.LoadAccountTokens(accountId)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range []string{
+		"DELETE FROM identities WHERE account_id = ?",
+		"DELETE FROM account_roles WHERE account_id = ?",
+		"DELETE FROM webauthn_credentials WHERE user_id = ?",
+		"DELETE FROM user_totp WHERE user_id = ?",
+		"DELETE FROM totp_recovery_codes WHERE user_id = ?",
+		"DELETE FROM email_verifications WHERE user_id = ?",
+		"DELETE FROM password_resets WHERE user_id = ?",
+		"DELETE FROM refresh_tokens WHERE user_id = ?",
+		"DELETE FROM sessions WHERE user_id = ?",
+		// 세션이 지워지면 남은 엑세스 토큰도 거부되므로 폐기 기록은 필요 없음
+		"DELETE FROM revoked_access_tokens WHERE user_id = ?",
+		"DELETE FROM access_token_cutoffs WHERE user_id = ?",
+		"DELETE FROM oauth_handoffs WHERE account_id = ?",
+	} {
+		if _, err = tx.Exec(query, accountId); err != nil {
+			return err
+		}
+	}
+
+	// 개인정보 내보내기 파일
+	var files []string
+	if err = tx.Select(&files, "SELECT file_path FROM data_exports WHERE account_id = ? AND file_path IS NOT NULL", accountId); err != nil {
+		return err
+	}
+	if _, err = tx.Exec("DELETE FROM data_exports WHERE account_id = ?", accountId); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	oauth.RevokeAll(providerTokens)
+	if avatarKey.Valid {
+		avatar.DeleteFiles(avatarKey.String)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			log.Println("내보내기 파일 삭제 실패: ", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,422 @@
+// admin/users.go
+package admin
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/audit"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/identity"
+	"guny-world-backend/api/password"
+	"guny-world-backend/api/register"
+	"guny-world-backend/api/role"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/twofactor"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jmoiron/sqlx"
+)
+
+// 한 페이지에 보여줄 검색 결과 수
+const pageSize = 20
+
+var errUserNotFound = errors.New("계정을 찾을 수 없습니다.")
+
+// 검색 결과의 계정 요약
+type userSummary struct {
+	Id          int64          `db:"id" json:"id"`
+	Email       sql.NullString `db:"email" json:"-"`
+	Nickname    string         `db:"nickname" json:"nickname"`
+	CreatedAt   time.Time      `db:"created_at" json:"createdAt"`
+	SuspendedAt sql.NullTime   `db:"suspended_at" json:"-"`
+	Until       sql.NullTime   `db:"suspended_until" json:"-"`
+}
+
+// 계정 검색 핸들러 (id 일치, 닉네임/이메일 부분 일치)
+func SearchUsers(c *fiber.Ctx) (err error) {
+	q := strings.TrimSpace(c.Query("q"))
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+
+	id, _ := strconv.ParseInt(q, 10, 64)
+	like := "%" + escapeLike(q) + "%"
+
+	users := []userSummary{}
+	err = database.DB.Select(&users, `SELECT id, email, nickname, created_at, suspended_at, suspended_until FROM accounts
+		WHERE id = ? OR nickname LIKE ? OR email LIKE ? OR id IN (SELECT account_id FROM identities WHERE email LIKE ?)
+		ORDER BY id DESC LIMIT ? OFFSET ?`, id, like, like, like, pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Println("계정 검색 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	results := make([]fiber.Map, 0, len(users))
+	for _, user := range users {
+		results = append(results, fiber.Map{
+			"id":        user.Id,
+			"email":     nullString(user.Email),
+			"nickname":  user.Nickname,
+			"createdAt": user.CreatedAt,
+			"suspended": user.SuspendedAt.Valid && (!user.Until.Valid || time.Now().Before(user.Until.Time)),
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"users": results, "page": page})
+}
+
+// 계정 상세 조회 핸들러 (로그인 수단, 역할, 정지 상태, 활성 세션)
+func GetUser(c *fiber.Ctx) (err error) {
+	id := c.Params("id")
+
+	var account struct {
+		Id               int64          `db:"id"`
+		Email            sql.NullString `db:"email"`
+		Nickname         string         `db:"nickname"`
+		HasPassword      bool           `db:"has_password"`
+		EmailVerifiedAt  sql.NullTime   `db:"email_verified_at"`
+		CreatedAt        time.Time      `db:"created_at"`
+		SuspendedAt      sql.NullTime   `db:"suspended_at"`
+		SuspendedUntil   sql.NullTime   `db:"suspended_until"`
+		SuspensionReason sql.NullString `db:"suspension_reason"`
+		Passkeys         int            `db:"passkeys"`
+	}
+	err = database.DB.Get(&account, `SELECT id, email, nickname, password IS NOT NULL AS has_password, email_verified_at, created_at, suspended_at, suspended_until, suspension_reason,
+		(SELECT COUNT(*) FROM webauthn_credentials WHERE user_id = accounts.id) AS passkeys
+		FROM accounts WHERE id = ?`, id)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "계정을 찾을 수 없습니다."})
+	} else if err != nil {
+		log.Println("계정 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	identities, err := identity.List(id)
+	if err != nil {
+		log.Println("연결된 로그인 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	twoFactorEnabled, err := twofactor.Enabled(id)
+	if err != nil {
+		log.Println("2단계 인증 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	roles, err := role.List(id)
+	if err != nil {
+		log.Println("역할 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	sessions, err := session.List(id)
+	if err != nil {
+		log.Println("세션 목록 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 기간이 끝난 정지도 기록으로 보여 줌
+	var suspension fiber.Map
+	if account.SuspendedAt.Valid {
+		suspension = fiber.Map{
+			"suspendedAt": account.SuspendedAt.Time,
+			"until":       nullTime(account.SuspendedUntil),
+			"permanent":   !account.SuspendedUntil.Valid,
+			"active":      !account.SuspendedUntil.Valid || time.Now().Before(account.SuspendedUntil.Time),
+			"reason":      account.SuspensionReason.String,
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"id":            account.Id,
+		"email":         nullString(account.Email),
+		"emailVerified": account.EmailVerifiedAt.Valid,
+		"nickname":      account.Nickname,
+		"createdAt":     account.CreatedAt,
+		"roles":         roles,
+		"loginMethods": fiber.Map{
+			"password":   account.HasPassword,
+			"identities": identities,
+			"passkeys":   account.Passkeys,
+			"twoFactor":  twoFactorEnabled,
+		},
+		"suspension": suspension,
+		"sessions":   sessions,
+	})
+}
+
+// 비밀번호 강제 재설정 핸들러
+// 현재 비밀번호를 사용할 수 없게 하고 모든 세션을 끊은 뒤 재설정 메일 발송
+func ForcePasswordReset(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+	id := c.Params("id")
+
+	var email sql.NullString
+	err = database.DB.Get(&email, "SELECT email FROM accounts WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "계정을 찾을 수 없습니다."})
+	} else if err != nil {
+		log.Println("계정 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if !email.Valid {
+		return c.Status(400).JSON(fiber.Map{"error": "이메일로 가입한 계정이 아닙니다."})
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		log.Println("트랜잭션 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec("UPDATE accounts SET password = NULL, updated_at = ? WHERE id = ?", time.Now(), id); err != nil {
+		log.Println("비밀번호 초기화 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if err = audit.Record(tx, principal.UserId, audit.ActionPasswordReset, id, nil, c.IP()); err != nil {
+		log.Println("관리자 작업 기록 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if err = tx.Commit(); err != nil {
+		log.Println("트랜잭션 커밋 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	if err := session.RevokeAllForUser(id); err != nil {
+		log.Println("세션 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "비밀번호는 초기화되었지만 기존 로그인을 해제하지 못했습니다."})
+	}
+	if err := password.SendResetLink(id, email.String); err != nil {
+		log.Println("재설정 메일 발송 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "비밀번호는 초기화되었지만 재설정 메일을 보내지 못했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "비밀번호를 초기화하고 재설정 메일을 보냈습니다."})
+}
+
+// 계정 정지 핸들러
+// until(RFC 3339) 까지 기간 정지하거나 permanent 로 영구 정지
+func SuspendUser(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+	id := c.Params("id")
+
+	type RequestQuery struct {
+		Reason    string     `json:"reason"`
+		Until     *time.Time `json:"until"`
+		Permanent bool       `json:"permanent"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+	if strings.TrimSpace(requestQuery.Reason) == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "정지 사유를 입력해 주세요."})
+	}
+	if requestQuery.Permanent == (requestQuery.Until != nil) {
+		return c.Status(400).JSON(fiber.Map{"error": "정지 종료일(until) 또는 영구 정지(permanent) 중 하나를 지정해 주세요."})
+	}
+	if requestQuery.Until != nil && !requestQuery.Until.After(time.Now()) {
+		return c.Status(400).JSON(fiber.Map{"error": "정지 종료일은 현재 이후여야 합니다."})
+	}
+	if id == principal.UserId {
+		return c.Status(400).JSON(fiber.Map{"error": "자기 계정은 정지할 수 없습니다."})
+	}
+
+	action := audit.ActionSuspend
+	detail := fiber.Map{"reason": requestQuery.Reason}
+	var until sql.NullTime
+	if requestQuery.Permanent {
+		action = audit.ActionBan
+	} else {
+		until = sql.NullTime{Time: *requestQuery.Until, Valid: true}
+		detail["until"] = requestQuery.Until
+	}
+
+	err = updateWithAudit(c, action, detail,
+		"UPDATE accounts SET suspended_at = ?, suspended_until = ?, suspension_reason = ? WHERE id = ?", time.Now(), until, requestQuery.Reason, id)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	// 이미 로그인된 기기는 모두 로그아웃
+	if err := session.RevokeAllForUser(id); err != nil {
+		log.Println("세션 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "계정은 정지되었지만 기존 로그인을 해제하지 못했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "계정이 정지되었습니다."})
+}
+
+// 계정 정지 해제 핸들러
+func UnsuspendUser(c *fiber.Ctx) (err error) {
+	err = updateWithAudit(c, audit.ActionUnsuspend, nil,
+		"UPDATE accounts SET suspended_at = NULL, suspended_until = NULL, suspension_reason = NULL WHERE id = ?", c.Params("id"))
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "계정 정지가 해제되었습니다."})
+}
+
+// 닉네임 변경 핸들러
+func UpdateNickname(c *fiber.Ctx) (err error) {
+	type RequestQuery struct {
+		Nickname string `json:"nickname"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+	if err := register.ValidateNickname(requestQuery.Nickname); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	id := c.Params("id")
+	var previous string
+	err = database.DB.Get(&previous, "SELECT nickname FROM accounts WHERE id = ?", id)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("계정 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	err = updateWithAudit(c, audit.ActionUpdateNickname, fiber.Map{"from": previous, "to": requestQuery.Nickname},
+		"UPDATE accounts SET nickname = ?, updated_at = ? WHERE id = ?", requestQuery.Nickname, time.Now(), id)
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "닉네임이 변경되었습니다."})
+}
+
+// 역할 부여 핸들러 (다음 토큰 재발급부터 roles 클레임에 반영)
+func GrantRole(c *fiber.Ctx) (err error) {
+	id := c.Params("id")
+	roleName := c.Params("role")
+	if !role.Valid(roleName) {
+		return c.Status(400).JSON(fiber.Map{"error": "알 수 없는 역할입니다."})
+	}
+
+	err = withAudit(c, audit.ActionGrantRole, fiber.Map{"role": roleName}, func(tx *sqlx.Tx) error {
+		return role.Grant(tx, id, roleName)
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "역할이 부여되었습니다."})
+}
+
+// 역할 회수 핸들러
+// 이미 발급된 엑세스 토큰에 역할이 남아 있으므로 대상 계정의 모든 기기를 로그아웃
+func RevokeRole(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+	id := c.Params("id")
+	roleName := c.Params("role")
+	if !role.Valid(roleName) {
+		return c.Status(400).JSON(fiber.Map{"error": "알 수 없는 역할입니다."})
+	}
+	if id == principal.UserId && roleName == role.Admin {
+		return c.Status(400).JSON(fiber.Map{"error": "자기 계정의 관리자 역할은 회수할 수 없습니다."})
+	}
+
+	err = withAudit(c, audit.ActionRevokeRole, fiber.Map{"role": roleName}, func(tx *sqlx.Tx) error {
+		return role.Revoke(tx, id, roleName)
+	})
+	if err != nil {
+		return respondError(c, err)
+	}
+
+	if err := session.RevokeAllForUser(id); err != nil {
+		log.Println("세션 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "역할은 회수되었지만 기존 로그인을 해제하지 못했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "역할이 회수되었습니다."})
+}
+
+// 관리자 작업 기록 조회 핸들러 (target_id 로 특정 계정만 조회)
+func GetAuditLogs(c *fiber.Ctx) (err error) {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+
+	entries, err := audit.List(c.Query("target_id"), pageSize, (page-1)*pageSize)
+	if err != nil {
+		log.Println("관리자 작업 기록 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"logs": entries, "page": page})
+}
+
+// 계정 하나를 수정하고 같은 트랜잭션으로 작업 기록 (마지막 인자가 대상 계정 ID)
+func updateWithAudit(c *fiber.Ctx, action string, detail fiber.Map, query string, args ...interface{}) error {
+	return withAudit(c, action, detail, func(tx *sqlx.Tx) error {
+		_, err := tx.Exec(query, args...)
+		return err
+	})
+}
+
+// 대상 계정(:id)을 잠그고 apply 실행 후 같은 트랜잭션으로 작업 기록
+func withAudit(c *fiber.Ctx, action string, detail fiber.Map, apply func(tx *sqlx.Tx) error) error {
+	principal := auth.GetPrincipal(c)
+	targetId := c.Params("id")
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists int
+	if err = tx.Get(&exists, "SELECT COUNT(*) FROM accounts WHERE id = ? FOR UPDATE", targetId); err != nil {
+		return err
+	}
+	if exists == 0 {
+		return errUserNotFound
+	}
+
+	if err = apply(tx); err != nil {
+		return err
+	}
+	if err = audit.Record(tx, principal.UserId, action, targetId, detail, c.IP()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func respondError(c *fiber.Ctx, err error) error {
+	if err == errUserNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+	log.Println("관리자 작업 실패: ", err)
+	return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+}
+
+// LIKE 패턴의 특수 문자 이스케이프
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func nullTime(value sql.NullTime) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.Time
+}
+
+func nullString(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.String
+}
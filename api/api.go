@@ -1,23 +1,90 @@
 package api
 
 import (
+	adminapi "guny-world-backend/api/admin"
+	auth "guny-world-backend/api/auth"
+	avatar "guny-world-backend/api/avatar"
 	chzzk "guny-world-backend/api/chzzk"
+	export "guny-world-backend/api/export"
 	handlers "guny-world-backend/api/handlers"
+	jwks "guny-world-backend/api/jwks"
 	login "guny-world-backend/api/login"
+	logout "guny-world-backend/api/logout"
+	password "guny-world-backend/api/password"
 	register "guny-world-backend/api/register"
 	reissue "guny-world-backend/api/reissue"
+	role "guny-world-backend/api/role"
+	storage "guny-world-backend/api/storage"
+	twofactor "guny-world-backend/api/twofactor"
+	verification "guny-world-backend/api/verification"
+	webauthn "guny-world-backend/api/webauthn"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func Setting(app *fiber.App) {
+	app.Get("/.well-known/jwks.json", jwks.JWKS)
+
+	// 로컬 저장소를 쓰면 업로드 파일을 직접 제공
+	if dir := storage.LocalDir(); dir != "" {
+		app.Static("/uploads", dir)
+	}
+
 	api := app.Group("/api")
 
 	api.Post("/register", register.Register)
+	api.Post("/verify-email/confirm", verification.Confirm)
+	api.Post("/verify-email/resend", verification.Resend)
 	api.Post("/login", login.Login)
+	api.Post("/login/2fa", login.VerifyTwoFactor)
+	api.Post("/login/passkey/begin", login.PasskeyBeginLimit, login.PasskeyBegin)
+	api.Post("/login/passkey/finish", login.PasskeyFinish)
 	api.Post("/reissue", reissue.Reissue)
-	api.Group("/naver/callback", login.NaverLogin)
+	api.Post("/password/reset", password.RequestReset)
+	api.Post("/password/reset/confirm", password.ConfirmReset)
+	api.Post("/logout", logout.Logout)
+	api.Post("/logout/all", auth.RequireAuth, logout.LogoutAll)
+	api.Get("/naver/callback", login.NaverCallback)
+	api.Get("/oauth/:provider/authorize", login.OAuthAuthorize)
+	api.Get("/oauth/:provider/callback", login.OAuthCallback)
+	api.Post("/oauth/exchange", login.OAuthExchange)
+	api.Get("/oauth/:provider/link", login.LinkIdentityStart)
+	api.Get("/exports/:id/download", export.Download)
+
+	// 인증 필요 (Authorization: Bearer <accessToken>)
+	api.Get("/user_info", auth.RequireAuth, handlers.GetUserInfo)
+	api.Get("/me", auth.RequireAuth, handlers.GetMe)
+	api.Patch("/me", auth.RequireAuth, handlers.UpdateMe)
+	api.Post("/me/avatar", auth.RequireAuth, avatar.Upload)
+	api.Delete("/me/avatar", auth.RequireAuth, avatar.Delete)
+	api.Delete("/account", auth.RequireAuth, handlers.DeleteAccount)
+	api.Post("/account/export", auth.RequireAuth, export.RequestExport)
+	api.Get("/account/export/:id", auth.RequireAuth, export.GetExport)
+	api.Get("/sessions", auth.RequireAuth, handlers.GetSessions)
+	api.Delete("/sessions/:id", auth.RequireAuth, handlers.DeleteSession)
+	api.Post("/password/change", auth.RequireAuth, password.Change)
+	api.Post("/2fa/enroll", auth.RequireAuth, twofactor.Enroll)
+	api.Post("/2fa/confirm", auth.RequireAuth, twofactor.ConfirmEnrollment)
+	api.Post("/2fa/disable", auth.RequireAuth, twofactor.Disable)
+	api.Post("/passkeys/register/begin", auth.RequireAuth, webauthn.RegisterBegin)
+	api.Post("/passkeys/register/finish", auth.RequireAuth, webauthn.RegisterFinish)
+	api.Get("/passkeys", auth.RequireAuth, webauthn.ListCredentials)
+	api.Delete("/passkeys/:id", auth.RequireAuth, webauthn.DeleteCredential)
+	api.Get("/identities", auth.RequireAuth, handlers.GetIdentities)
+	api.Post("/identities/:provider/link/begin", auth.RequireAuth, login.LinkIdentityBegin)
+	api.Delete("/identities/:provider", auth.RequireAuth, handlers.DeleteIdentity)
+
+	// 관리자 (admin 역할 필요)
+	admin := api.Group("/admin", auth.RequireAuth, auth.RequireRole(role.Admin))
+	admin.Get("/users", adminapi.SearchUsers)
+	admin.Get("/users/:id", adminapi.GetUser)
+	admin.Post("/users/:id/password-reset", adminapi.ForcePasswordReset)
+	admin.Post("/users/:id/suspend", adminapi.SuspendUser)
+	admin.Post("/users/:id/unsuspend", adminapi.UnsuspendUser)
+	admin.Patch("/users/:id/nickname", adminapi.UpdateNickname)
+	admin.Put("/users/:id/roles/:role", adminapi.GrantRole)
+	admin.Delete("/users/:id/roles/:role", adminapi.RevokeRole)
+	admin.Get("/audit-logs", adminapi.GetAuditLogs)
 
-	api.Get("/user_info", handlers.GetUserInfo)
 	api.Post("/chzzk", chzzk.Chzzk)
 }
@@ -0,0 +1,60 @@
+// audit/audit.go
+package audit
+
+import (
+	"encoding/json"
+	"guny-world-backend/api/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// 관리자 작업 종류
+const (
+	ActionPasswordReset  = "user.password_reset"
+	ActionSuspend        = "user.suspend"
+	ActionBan            = "user.ban"
+	ActionUnsuspend      = "user.unsuspend"
+	ActionUpdateNickname = "user.update_nickname"
+	ActionGrantRole      = "user.grant_role"
+	ActionRevokeRole     = "user.revoke_role"
+)
+
+// 관리자 작업 기록
+type Entry struct {
+	Id        int64          `db:"id" json:"id"`
+	ActorId   string         `db:"actor_id" json:"actorId"`
+	Action    string         `db:"action" json:"action"`
+	TargetId  string         `db:"target_id" json:"targetId"`
+	Detail    types.JSONText `db:"detail" json:"detail"`
+	Ip        string         `db:"ip" json:"ip"`
+	CreatedAt time.Time      `db:"created_at" json:"createdAt"`
+}
+
+// 작업 기록 저장 (작업과 같은 트랜잭션으로 넘기면 함께 커밋/롤백)
+func Record(db sqlx.Execer, actorId, action, targetId string, detail map[string]interface{}, ip string) error {
+	if detail == nil {
+		detail = map[string]interface{}{}
+	}
+	encoded, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("INSERT INTO admin_audit_logs (actor_id, action, target_id, detail, ip, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		actorId, action, targetId, encoded, ip, time.Now())
+	return err
+}
+
+// 작업 기록 조회 (targetId 가 비어 있으면 전체, 최신순)
+func List(targetId string, limit, offset int) ([]Entry, error) {
+	entries := []Entry{}
+	var err error
+	if targetId == "" {
+		err = database.DB.Select(&entries, "SELECT id, actor_id, action, target_id, detail, ip, created_at FROM admin_audit_logs ORDER BY id DESC LIMIT ? OFFSET ?", limit, offset)
+	} else {
+		err = database.DB.Select(&entries, "SELECT id, actor_id, action, target_id, detail, ip, created_at FROM admin_audit_logs WHERE target_id = ? ORDER BY id DESC LIMIT ? OFFSET ?", targetId, limit, offset)
+	}
+	return entries, err
+}
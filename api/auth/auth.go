@@ -0,0 +1,111 @@
+// auth/auth.go
+package auth
+
+import (
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/suspension"
+	"guny-world-backend/api/token"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fiber.Ctx.Locals 에 Principal 을 저장하는 키
+const principalKey = "principal"
+
+// 인증된 요청의 사용자 정보
+type Principal struct {
+	UserId    string
+	SessionId string
+	TokenId   string
+	Roles     []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Authorization: Bearer 헤더에서 토큰 추출
+func BearerToken(c *fiber.Ctx) string {
+	header := c.Get(fiber.HeaderAuthorization)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[7:])
+}
+
+// 엑세스 토큰 인증 미들웨어
+// 검증에 성공하면 Principal 을 Locals 에 저장하고 다음 핸들러로 넘김
+func RequireAuth(c *fiber.Ctx) error {
+	tokenString := BearerToken(c)
+	if tokenString == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "인증 토큰이 존재하지 않습니다."})
+	}
+
+	claims, err := token.Default().Parse(tokenString, token.TypeAccess)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "유효하지 않거나 만료된 토큰입니다."})
+	}
+
+	// 정지된 계정이면 사유와 종료일을 알려 줌 (정지 시 토큰도 폐기되므로 폐기 확인보다 먼저)
+	s, err := suspension.Get(claims.UserId)
+	if err != nil {
+		log.Println("계정 정지 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if s != nil {
+		return suspension.Reject(c, s)
+	}
+
+	// 로그아웃으로 폐기된 토큰인지 확인
+	revoked, err := session.IsAccessTokenRevoked(claims.Id, claims.SessionId, claims.UserId, claims.IssuedAt)
+	if err != nil {
+		log.Println("엑세스 토큰 폐기 여부 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if revoked {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "로그아웃된 토큰입니다. 다시 로그인해 주세요."})
+	}
+
+	c.Locals(principalKey, &Principal{
+		UserId:    claims.UserId,
+		SessionId: claims.SessionId,
+		TokenId:   claims.Id,
+		Roles:     claims.Roles,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	})
+	return c.Next()
+}
+
+// RequireAuth 가 저장한 Principal 조회 (미들웨어를 거치지 않았으면 nil)
+func GetPrincipal(c *fiber.Ctx) *Principal {
+	principal, _ := c.Locals(principalKey).(*Principal)
+	return principal
+}
+
+// 역할 확인 미들웨어 (RequireAuth 다음에 사용, 하나라도 있으면 통과)
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := GetPrincipal(c)
+		if principal == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "인증 토큰이 존재하지 않습니다."})
+		}
+		for _, role := range roles {
+			if principal.HasRole(role) {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "권한이 없습니다."})
+	}
+}
+
+// 역할 보유 여부
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
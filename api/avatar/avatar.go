@@ -0,0 +1,146 @@
+// avatar/avatar.go
+package avatar
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/storage"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// 크기별 썸네일 주소 (avatar_key 가 없으면 nil)
+func Thumbnails(key sql.NullString) map[string]string {
+	if !key.Valid {
+		return nil
+	}
+	urls := make(map[string]string, len(Sizes))
+	for _, size := range Sizes {
+		urls[strconv.Itoa(size)] = storage.Default().URL(objectKey(key.String, size))
+	}
+	return urls
+}
+
+// 저장된 썸네일 모두 삭제 (실패해도 로그만 남김)
+func DeleteFiles(key string) {
+	for _, size := range Sizes {
+		if err := storage.Default().Delete(objectKey(key, size)); err != nil {
+			log.Println("프로필 이미지 삭제 실패: ", err)
+		}
+	}
+}
+
+// 업로드마다 새 경로를 써서 CDN/브라우저 캐시가 예전 이미지를 보여주지 않도록 함
+func objectKey(key string, size int) string {
+	return key + "/" + strconv.Itoa(size) + ".jpg"
+}
+
+// 프로필 이미지 업로드 핸들러 (auth.RequireAuth 뒤에서 동작, multipart 필드 image)
+func Upload(c *fiber.Ctx) (err error) {
+	userId := auth.GetPrincipal(c).UserId
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "이미지 파일이 존재하지 않습니다."})
+	}
+	if file.Size > maxUploadBytes {
+		return c.Status(413).JSON(fiber.Map{"error": ErrTooLarge.Error()})
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		log.Println("업로드 파일 열기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadBytes+1))
+	if err != nil {
+		log.Println("업로드 파일 읽기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	thumbnails, err := process(data)
+	if errors.Is(err, ErrTooLarge) {
+		return c.Status(413).JSON(fiber.Map{"error": err.Error()})
+	} else if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrDimensions) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("프로필 이미지 변환 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	key := "avatars/" + userId + "/" + uuid.NewString()
+	for _, size := range Sizes {
+		if err := storage.Default().Put(objectKey(key, size), thumbnails[size], "image/jpeg"); err != nil {
+			log.Println("프로필 이미지 저장 실패: ", err)
+			DeleteFiles(key)
+			return c.Status(500).JSON(fiber.Map{"error": "이미지를 저장하는 데 실패했습니다."})
+		}
+	}
+
+	avatarKey := sql.NullString{String: key, Valid: true}
+	previous, err := replace(userId, avatarKey)
+	if err != nil {
+		log.Println("프로필 이미지 변경 실패: ", err)
+		DeleteFiles(key)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if previous.Valid {
+		DeleteFiles(previous.String)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"profileImage": storage.Default().URL(objectKey(key, Sizes[0])),
+		"thumbnails":   Thumbnails(avatarKey),
+	})
+}
+
+// 프로필 이미지 삭제 핸들러 (auth.RequireAuth 뒤에서 동작)
+func Delete(c *fiber.Ctx) (err error) {
+	userId := auth.GetPrincipal(c).UserId
+
+	previous, err := replace(userId, sql.NullString{})
+	if err != nil {
+		log.Println("프로필 이미지 삭제 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if previous.Valid {
+		DeleteFiles(previous.String)
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "프로필 이미지가 삭제되었습니다."})
+}
+
+// 프로필 이미지를 바꾸고 이전 avatar_key 반환
+// 외부 로그인에서 받아온 이미지 주소도 함께 덮어씀
+func replace(userId string, key sql.NullString) (sql.NullString, error) {
+	var previous sql.NullString
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return previous, err
+	}
+	defer tx.Rollback()
+
+	err = tx.Get(&previous, "SELECT avatar_key FROM accounts WHERE id = ? FOR UPDATE", userId)
+	if err != nil {
+		return previous, err
+	}
+
+	var profileImage sql.NullString
+	if key.Valid {
+		profileImage = sql.NullString{String: storage.Default().URL(objectKey(key.String, Sizes[0])), Valid: true}
+	}
+	_, err = tx.Exec("UPDATE accounts SET avatar_key = ?, profile_image = ?, updated_at = ? WHERE id = ?", key, profileImage, time.Now(), userId)
+	if err != nil {
+		return previous, err
+	}
+	return previous, tx.Commit()
+}
@@ -0,0 +1,198 @@
+// avatar/image.go
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// 업로드 최대 크기 (fiber 기본 BodyLimit 4MB 이내)
+	maxUploadBytes = 3 << 20
+	// 가로/세로 최대 픽셀 (압축 폭탄 방지)
+	maxDimension = 4096
+	jpegQuality  = 85
+)
+
+// 만들 썸네일 크기 (정사각형, 첫 번째가 대표 이미지)
+var Sizes = []int{256, 128, 64}
+
+var (
+	ErrTooLarge    = errors.New("이미지는 3MB 이하만 올릴 수 있습니다.")
+	ErrUnsupported = errors.New("JPEG, PNG, GIF, WebP 이미지만 올릴 수 있습니다.")
+	ErrDimensions  = errors.New("이미지는 가로, 세로 4096픽셀 이하여야 합니다.")
+)
+
+// 파일 내용으로 판별한 허용 형식 (확장자나 Content-Type 헤더는 믿지 않음)
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// 업로드 이미지를 가운데 기준 정사각형으로 잘라 크기별 JPEG 로 변환
+// 다시 인코딩하므로 EXIF 등 메타데이터는 모두 제거되고, 회전 정보만 픽셀에 반영
+func process(data []byte) (map[int][]byte, error) {
+	if len(data) > maxUploadBytes {
+		return nil, ErrTooLarge
+	}
+	if !allowedTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupported
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+	if config.Width > maxDimension || config.Height > maxDimension || config.Width == 0 || config.Height == 0 {
+		return nil, ErrDimensions
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupported
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = exifOrientation(data)
+	}
+
+	// 가운데 정사각형 (회전/뒤집기와 순서를 바꿔도 결과가 같으므로 줄인 뒤 회전)
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(x0, y0, x0+side, y0+side)
+
+	thumbnails := make(map[int][]byte, len(Sizes))
+	for _, size := range Sizes {
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		// 투명 배경은 흰색으로 (JPEG 는 알파 채널이 없음)
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, orient(dst, orientation), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+	return thumbnails, nil
+}
+
+// JPEG 의 EXIF Orientation 값 (없거나 읽을 수 없으면 1)
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// 영상 데이터가 시작되면 더 이상 메타데이터 없음
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// TIFF 헤더와 IFD0 에서 0x0112(Orientation) 태그 찾기
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			value := int(order.Uint16(tiff[entry+8:]))
+			if value < 1 || value > 8 {
+				return 1
+			}
+			return value
+		}
+	}
+	return 1
+}
+
+// EXIF Orientation 에 맞게 회전/뒤집기 (정사각형 이미지 기준)
+func orient(img *image.RGBA, orientation int) image.Image {
+	if orientation == 1 {
+		return img
+	}
+
+	size := img.Bounds().Dx()
+	last := size - 1
+	dst := image.NewRGBA(img.Bounds())
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			// 결과 (x, y) 에 올 원본 좌표
+			var sx, sy int
+			switch orientation {
+			case 2: // 좌우 반전
+				sx, sy = last-x, y
+			case 3: // 180도
+				sx, sy = last-x, last-y
+			case 4: // 상하 반전
+				sx, sy = x, last-y
+			case 5: // 좌우 반전 후 90도 반시계
+				sx, sy = y, x
+			case 6: // 90도 시계
+				sx, sy = y, last-x
+			case 7: // 좌우 반전 후 90도 시계
+				sx, sy = last-y, last-x
+			case 8: // 90도 반시계
+				sx, sy = last-y, x
+			default:
+				return img
+			}
+			dst.SetRGBA(x, y, img.RGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,283 @@
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+var (
+	red    = color.RGBA{255, 0, 0, 255}
+	green  = color.RGBA{0, 255, 0, 255}
+	blue   = color.RGBA{0, 0, 255, 255}
+	yellow = color.RGBA{255, 255, 0, 255}
+)
+
+// 사분면마다 색이 다른 이미지 (왼쪽 위 빨강, 오른쪽 위 초록, 왼쪽 아래 파랑, 오른쪽 아래 노랑)
+func quadrantImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := red
+			switch {
+			case x >= width/2 && y < height/2:
+				c = green
+			case x < width/2 && y >= height/2:
+				c = blue
+			case x >= width/2 && y >= height/2:
+				c = yellow
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// SOI 바로 뒤에 Orientation 태그가 든 EXIF APP1 세그먼트 삽입
+func withOrientation(data []byte, order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+2*12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 2)
+
+	// Orientation 앞에 다른 태그 (ImageWidth) 하나
+	entry := tiff[10:]
+	order.PutUint16(entry[0:], 0x0100)
+	order.PutUint16(entry[2:], 3)
+	order.PutUint32(entry[4:], 1)
+	order.PutUint16(entry[8:], 64)
+
+	entry = tiff[22:]
+	order.PutUint16(entry[0:], 0x0112)
+	order.PutUint16(entry[2:], 3)
+	order.PutUint32(entry[4:], 1)
+	order.PutUint16(entry[8:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+// JPEG 손실을 감안해 가장 가까운 사분면 색 이름
+func nearest(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	candidates := map[string]color.RGBA{"red": red, "green": green, "blue": blue, "yellow": yellow}
+	best, bestDistance := "", int64(-1)
+	for name, candidate := range candidates {
+		dr := int64(r>>8) - int64(candidate.R)
+		dg := int64(g>>8) - int64(candidate.G)
+		db := int64(b>>8) - int64(candidate.B)
+		distance := dr*dr + dg*dg + db*db
+		if bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+func decodeThumbnail(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("썸네일 디코딩 실패: %v", err)
+	}
+	if format != "jpeg" {
+		t.Fatalf("썸네일 형식 = %s, want jpeg", format)
+	}
+	return img
+}
+
+func TestProcessOrientation(t *testing.T) {
+	// 보이는 화면의 [왼쪽 위, 오른쪽 위, 왼쪽 아래, 오른쪽 아래]
+	cases := []struct {
+		orientation uint16
+		corners     [4]string
+	}{
+		{1, [4]string{"red", "green", "blue", "yellow"}},
+		{2, [4]string{"green", "red", "yellow", "blue"}},
+		{3, [4]string{"yellow", "blue", "green", "red"}},
+		{4, [4]string{"blue", "yellow", "red", "green"}},
+		{5, [4]string{"red", "blue", "green", "yellow"}},
+		{6, [4]string{"blue", "red", "yellow", "green"}},
+		{7, [4]string{"yellow", "green", "blue", "red"}},
+		{8, [4]string{"green", "yellow", "red", "blue"}},
+	}
+
+	raw := encodeJPEG(t, quadrantImage(96, 96))
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for _, tc := range cases {
+			data := withOrientation(raw, order, tc.orientation)
+			if got := exifOrientation(data); got != int(tc.orientation) {
+				t.Fatalf("%s orientation %d: exifOrientation = %d", order, tc.orientation, got)
+			}
+
+			thumbnails, err := process(data)
+			if err != nil {
+				t.Fatalf("%s orientation %d: %v", order, tc.orientation, err)
+			}
+			img := decodeThumbnail(t, thumbnails[64])
+			got := [4]string{
+				nearest(img.At(16, 16)),
+				nearest(img.At(47, 16)),
+				nearest(img.At(16, 47)),
+				nearest(img.At(47, 47)),
+			}
+			if got != tc.corners {
+				t.Errorf("%s orientation %d: 사분면 = %v, want %v", order, tc.orientation, got, tc.corners)
+			}
+		}
+	}
+}
+
+func TestExifOrientationIgnoresInvalid(t *testing.T) {
+	raw := encodeJPEG(t, quadrantImage(8, 8))
+
+	if got := exifOrientation(raw); got != 1 {
+		t.Errorf("EXIF 없음: %d, want 1", got)
+	}
+	for _, value := range []uint16{0, 9, 0xFFFF} {
+		if got := exifOrientation(withOrientation(raw, binary.BigEndian, value)); got != 1 {
+			t.Errorf("Orientation %d: %d, want 1", value, got)
+		}
+	}
+
+	// 길이가 잘린 세그먼트
+	data := withOrientation(raw, binary.BigEndian, 6)
+	if got := exifOrientation(data[:20]); got != 1 {
+		t.Errorf("잘린 EXIF: %d, want 1", got)
+	}
+	if got := exifOrientation([]byte{0xFF, 0xD8}); got != 1 {
+		t.Errorf("SOI 만 있음: %d, want 1", got)
+	}
+	if got := exifOrientation(encodePNG(t, quadrantImage(8, 8))); got != 1 {
+		t.Errorf("PNG: %d, want 1", got)
+	}
+}
+
+func TestProcessOutput(t *testing.T) {
+	data := withOrientation(encodeJPEG(t, quadrantImage(300, 200)), binary.BigEndian, 1)
+	thumbnails, err := process(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thumbnails) != len(Sizes) {
+		t.Fatalf("썸네일 수 = %d, want %d", len(thumbnails), len(Sizes))
+	}
+	for _, size := range Sizes {
+		img := decodeThumbnail(t, thumbnails[size])
+		if b := img.Bounds(); b.Dx() != size || b.Dy() != size {
+			t.Errorf("%d 썸네일 크기 = %v", size, b)
+		}
+		// 다시 인코딩하므로 EXIF 가 남지 않음
+		if bytes.Contains(thumbnails[size], []byte("Exif")) {
+			t.Errorf("%d 썸네일에 EXIF 가 남아 있음", size)
+		}
+	}
+}
+
+func TestProcessTransparentBackground(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	thumbnails, err := process(encodePNG(t, img))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, g, b, _ := decodeThumbnail(t, thumbnails[64]).At(32, 32).RGBA()
+	if r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("투명 배경 = (%d, %d, %d), want 흰색", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestProcessFormats(t *testing.T) {
+	img := quadrantImage(16, 16)
+
+	var gifData bytes.Buffer
+	if err := gif.Encode(&gifData, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string][]byte{
+		"jpeg": encodeJPEG(t, img),
+		"png":  encodePNG(t, img),
+		"gif":  gifData.Bytes(),
+	} {
+		if _, err := process(data); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestProcessRejectsUnsupported(t *testing.T) {
+	png := encodePNG(t, quadrantImage(16, 16))
+	cases := map[string][]byte{
+		"empty": {},
+		"html":  []byte("<html><body><img src=x onerror=alert(1)></body></html>"),
+		"svg":   []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="10" height="10"></svg>`),
+		"text":  []byte("just some text"),
+		"bmp":   append([]byte("BM"), make([]byte, 64)...),
+		// 형식 표시는 PNG 지만 내용이 깨짐
+		"broken png": append(append([]byte{}, png[:16]...), bytes.Repeat([]byte{0}, 32)...),
+	}
+	for name, data := range cases {
+		if _, err := process(data); err != ErrUnsupported {
+			t.Errorf("%s: %v, want ErrUnsupported", name, err)
+		}
+	}
+}
+
+func TestProcessRejectsTooLarge(t *testing.T) {
+	data := make([]byte, maxUploadBytes+1)
+	copy(data, encodePNG(t, quadrantImage(8, 8)))
+	if _, err := process(data); err != ErrTooLarge {
+		t.Errorf("%d 바이트: %v, want ErrTooLarge", len(data), err)
+	}
+}
+
+func TestProcessDimensions(t *testing.T) {
+	cases := []struct {
+		width, height int
+		want          error
+	}{
+		{maxDimension, 1, nil},
+		{1, maxDimension, nil},
+		{maxDimension + 1, 1, ErrDimensions},
+		{1, maxDimension + 1, ErrDimensions},
+	}
+	for _, tc := range cases {
+		data := encodePNG(t, image.NewGray(image.Rect(0, 0, tc.width, tc.height)))
+		if _, err := process(data); err != tc.want {
+			t.Errorf("%dx%d: %v, want %v", tc.width, tc.height, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// cleanup/cleanup.go
+package cleanup
+
+import (
+	"guny-world-backend/api/database"
+	"log"
+	"time"
+)
+
+// 정리 작업 실행 간격
+const interval = time.Hour
+
+// 한 번에 지우는 최대 행 수 (긴 잠금 방지)
+const batchSize = 1000
+
+// expires_at 이 지나면 더 이상 쓰이지 않는 기록
+// (만료된 챌린지/state/교환 코드/연결 티켓/리프레시 토큰은 조회해도 거부되고, 폐기 목록과 실패 기록은 만료 후 의미 없음)
+var expiringTables = []string{
+	"webauthn_challenges",
+	"oauth_states",
+	"oauth_handoffs",
+	"oauth_link_tickets",
+	"refresh_tokens",
+	"revoked_access_tokens",
+	"login_failures",
+}
+
+// 만료된 기록을 주기적으로 삭제 (서버 시작 시 호출)
+func StartJob() {
+	go func() {
+		for {
+			if err := Run(time.Now()); err != nil {
+				log.Println("만료 기록 정리 실패: ", err)
+			}
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// now 이전에 만료된 기록 삭제
+func Run(now time.Time) error {
+	for _, table := range expiringTables {
+		for {
+			result, err := database.DB.Exec("DELETE FROM "+table+" WHERE expires_at < ? LIMIT ?", now, batchSize)
+			if err != nil {
+				return err
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			if affected < batchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// export/archive.go
+package export
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/identity"
+	"guny-world-backend/api/role"
+	"guny-world-backend/api/twofactor"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+)
+
+// 압축 파일에 함께 넣는 안내문
+const readme = `거니월드 개인정보 열람 자료
+
+profile.json         계정 정보
+identities.json      연결된 외부 로그인 (네이버, 카카오 등). 외부 서비스 토큰은 보안상 포함하지 않습니다.
+login_history.json   로그인 기기(세션) 기록
+passkeys.json        등록한 패스키
+security.json        2단계 인증 사용 여부, 역할
+admin_actions.json   운영자가 이 계정에 대해 수행한 작업 (정지, 닉네임 변경 등)
+
+게임 데이터와 치지직 팔로워 조회 결과는 서버에 저장하지 않으므로 포함되지 않습니다.
+비밀번호, 2단계 인증 시크릿, 복구 코드는 복원할 수 없는 형태로만 저장하므로 포함되지 않습니다.
+`
+
+// 계정의 개인정보를 모아 path 에 ZIP 파일로 저장
+func writeArchive(path string, accountId string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if err := writeFile(archive, "README.txt", []byte(readme)); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		name    string
+		collect func(accountId string) (interface{}, error)
+	}{
+		{"profile.json", collectProfile},
+		{"identities.json", collectIdentities},
+		{"login_history.json", collectLoginHistory},
+		{"passkeys.json", collectPasskeys},
+		{"security.json", collectSecurity},
+		{"admin_actions.json", collectAdminActions},
+	}
+	for _, section := range sections {
+		data, err := section.collect(accountId)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFile(archive, section.name, encoded); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func writeFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func collectProfile(accountId string) (interface{}, error) {
+	var profile struct {
+		Id              int64          `db:"id" json:"id"`
+		Email           sql.NullString `db:"email" json:"email"`
+		Nickname        string         `db:"nickname" json:"nickname"`
+		Name            sql.NullString `db:"name" json:"name"`
+		ProfileImage    sql.NullString `db:"profile_image" json:"profileImage"`
+		EmailVerifiedAt sql.NullTime   `db:"email_verified_at" json:"emailVerifiedAt"`
+		CreatedAt       time.Time      `db:"created_at" json:"createdAt"`
+		UpdatedAt       sql.NullTime   `db:"updated_at" json:"updatedAt"`
+	}
+	err := database.DB.Get(&profile, "SELECT id, email, nickname, name, profile_image, email_verified_at, created_at, updated_at FROM accounts WHERE id = ?", accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"id":              profile.Id,
+		"email":           nullable(profile.Email.String, profile.Email.Valid),
+		"nickname":        profile.Nickname,
+		"name":            nullable(profile.Name.String, profile.Name.Valid),
+		"profileImage":    nullable(profile.ProfileImage.String, profile.ProfileImage.Valid),
+		"emailVerifiedAt": nullable(profile.EmailVerifiedAt.Time, profile.EmailVerifiedAt.Valid),
+		"createdAt":       profile.CreatedAt,
+		"updatedAt":       nullable(profile.UpdatedAt.Time, profile.UpdatedAt.Valid),
+	}, nil
+}
+
+func collectIdentities(accountId string) (interface{}, error) {
+	identities, err := identity.List(accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(identities))
+	for _, linked := range identities {
+		result = append(result, map[string]interface{}{
+			"provider":  linked.Provider,
+			"email":     nullable(linked.Email.String, linked.Email.Valid),
+			"createdAt": linked.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func collectLoginHistory(accountId string) (interface{}, error) {
+	var sessions []struct {
+		LoginMethod string       `db:"login_method" json:"loginMethod"`
+		UserAgent   string       `db:"user_agent" json:"userAgent"`
+		Ip          string       `db:"ip" json:"ip"`
+		CreatedAt   time.Time    `db:"created_at" json:"createdAt"`
+		LastUsedAt  time.Time    `db:"last_used_at" json:"lastUsedAt"`
+		RevokedAt   sql.NullTime `db:"revoked_at" json:"-"`
+	}
+	err := database.DB.Select(&sessions, "SELECT login_method, user_agent, ip, created_at, last_used_at, revoked_at FROM sessions WHERE user_id = ? ORDER BY created_at", accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, map[string]interface{}{
+			"loginMethod": s.LoginMethod,
+			"userAgent":   s.UserAgent,
+			"ip":          s.Ip,
+			"createdAt":   s.CreatedAt,
+			"lastUsedAt":  s.LastUsedAt,
+			"loggedOutAt": nullable(s.RevokedAt.Time, s.RevokedAt.Valid),
+		})
+	}
+	return result, nil
+}
+
+func collectPasskeys(accountId string) (interface{}, error) {
+	var passkeys []struct {
+		Name       string       `db:"name"`
+		CreatedAt  time.Time    `db:"created_at"`
+		LastUsedAt sql.NullTime `db:"last_used_at"`
+	}
+	err := database.DB.Select(&passkeys, "SELECT name, created_at, last_used_at FROM webauthn_credentials WHERE user_id = ? ORDER BY created_at", accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		result = append(result, map[string]interface{}{
+			"name":       passkey.Name,
+			"createdAt":  passkey.CreatedAt,
+			"lastUsedAt": nullable(passkey.LastUsedAt.Time, passkey.LastUsedAt.Valid),
+		})
+	}
+	return result, nil
+}
+
+func collectSecurity(accountId string) (interface{}, error) {
+	twoFactorEnabled, err := twofactor.Enabled(accountId)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := role.List(accountId)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"twoFactorEnabled": twoFactorEnabled, "roles": roles}, nil
+}
+
+// 운영자 작업 기록 (처리한 운영자 계정은 제외)
+func collectAdminActions(accountId string) (interface{}, error) {
+	var actions []struct {
+		Action    string         `db:"action"`
+		Detail    types.JSONText `db:"detail"`
+		CreatedAt time.Time      `db:"created_at"`
+	}
+	err := database.DB.Select(&actions, "SELECT action, detail, created_at FROM admin_audit_logs WHERE target_id = ? ORDER BY id", accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, map[string]interface{}{
+			"action":    action.Action,
+			"detail":    action.Detail,
+			"createdAt": action.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+func nullable(value interface{}, valid bool) interface{} {
+	if !valid {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,215 @@
+// export/export.go
+package export
+
+import (
+	"database/sql"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// 내보내기 상태
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+	StatusExpired = "expired"
+)
+
+const (
+	// 만든 압축 파일 보관 기간
+	archiveTTL = time.Hour * 24 * 7
+	// 다운로드 링크 유효 기간
+	downloadLinkTTL = time.Minute * 15
+	// 같은 계정의 새 내보내기 요청 최소 간격
+	requestInterval = time.Hour * 24
+	// 이 시간이 지나도록 pending 이면 실패 처리 (생성 중 서버 재시작 등)
+	pendingTimeout = time.Hour
+	// 만료 파일 정리 간격
+	cleanupInterval = time.Hour
+)
+
+// 내보내기 요청
+type dataExport struct {
+	Id                string         `db:"id"`
+	AccountId         string         `db:"account_id"`
+	Status            string         `db:"status"`
+	FilePath          sql.NullString `db:"file_path"`
+	DownloadTokenHash sql.NullString `db:"download_token_hash"`
+	DownloadExpiresAt sql.NullTime   `db:"download_expires_at"`
+	ExpiresAt         sql.NullTime   `db:"expires_at"`
+	CreatedAt         time.Time      `db:"created_at"`
+	CompletedAt       sql.NullTime   `db:"completed_at"`
+}
+
+const selectExport = "SELECT id, account_id, status, file_path, download_token_hash, download_expires_at, expires_at, created_at, completed_at FROM data_exports"
+
+// 압축 파일 저장 위치 (DATA_EXPORT_DIR, 기본값 임시 디렉터리)
+func exportDir() string {
+	if dir := os.Getenv("DATA_EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join(os.TempDir(), "guny-world-exports")
+}
+
+// 개인정보 내보내기 요청 핸들러 (압축 파일은 백그라운드에서 생성)
+func RequestExport(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	// 하루 한 번까지, 진행 중이거나 최근에 만든 요청이 있으면 그것을 안내
+	var recent dataExport
+	err = database.DB.Get(&recent, selectExport+" WHERE account_id = ? AND status IN (?, ?) AND created_at > ? ORDER BY created_at DESC LIMIT 1",
+		principal.UserId, StatusPending, StatusReady, time.Now().Add(-requestInterval))
+	if err == nil {
+		retryAfter := time.Until(recent.CreatedAt.Add(requestInterval))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())+1))
+		return c.Status(429).JSON(fiber.Map{"error": "개인정보 내보내기는 하루에 한 번 요청할 수 있습니다.", "exportId": recent.Id, "status": recent.Status})
+	} else if err != sql.ErrNoRows {
+		log.Println("내보내기 요청 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	id := uuid.NewString()
+	_, err = database.DB.Exec("INSERT INTO data_exports (id, account_id, status, created_at) VALUES (?, ?, ?, ?)", id, principal.UserId, StatusPending, time.Now())
+	if err != nil {
+		log.Println("내보내기 요청 저장 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	go build(id, principal.UserId)
+
+	return c.Status(202).JSON(fiber.Map{"message": "개인정보 내보내기를 준비하고 있습니다.", "exportId": id, "status": StatusPending})
+}
+
+// 내보내기 상태 조회 핸들러 (준비가 끝났으면 새 다운로드 링크 발급)
+func GetExport(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	var export dataExport
+	err = database.DB.Get(&export, selectExport+" WHERE id = ? AND account_id = ?", c.Params("id"), principal.UserId)
+	if err == sql.ErrNoRows {
+		return c.Status(404).JSON(fiber.Map{"error": "내보내기 요청을 찾을 수 없습니다."})
+	} else if err != nil {
+		log.Println("내보내기 요청 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	response := fiber.Map{"exportId": export.Id, "status": export.Status, "createdAt": export.CreatedAt}
+	if export.Status != StatusReady {
+		return c.Status(200).JSON(response)
+	}
+
+	// 다운로드 링크는 조회할 때마다 새로 발급하고 이전 링크는 무효
+	token, hash, err := onetime.New()
+	if err != nil {
+		log.Println("다운로드 토큰 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	linkExpiresAt := time.Now().Add(downloadLinkTTL)
+	if export.ExpiresAt.Valid && export.ExpiresAt.Time.Before(linkExpiresAt) {
+		linkExpiresAt = export.ExpiresAt.Time
+	}
+	_, err = database.DB.Exec("UPDATE data_exports SET download_token_hash = ?, download_expires_at = ? WHERE id = ?", hash, linkExpiresAt, export.Id)
+	if err != nil {
+		log.Println("다운로드 토큰 저장 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	response["downloadUrl"] = "/api/exports/" + export.Id + "/download?token=" + url.QueryEscape(token)
+	response["downloadExpiresAt"] = linkExpiresAt
+	response["archiveExpiresAt"] = export.ExpiresAt.Time
+	return c.Status(200).JSON(response)
+}
+
+// 압축 파일 다운로드 핸들러 (브라우저에서 바로 열 수 있도록 토큰으로 인증)
+func Download(c *fiber.Ctx) (err error) {
+	var export dataExport
+	err = database.DB.Get(&export, selectExport+" WHERE id = ?", c.Params("id"))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("내보내기 요청 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	token := c.Query("token")
+	if err == sql.ErrNoRows || token == "" || export.Status != StatusReady || !export.FilePath.Valid ||
+		export.DownloadTokenHash.String != onetime.Hash(token) || !time.Now().Before(export.DownloadExpiresAt.Time) {
+		return c.Status(404).JSON(fiber.Map{"error": "만료되었거나 유효하지 않은 다운로드 링크입니다."})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Download(export.FilePath.String, "guny-world-data-"+export.CreatedAt.Format("20060102")+".zip")
+}
+
+// 압축 파일 생성
+func build(id, accountId string) {
+	dir := exportDir()
+	path := filepath.Join(dir, id+".zip")
+
+	err := os.MkdirAll(dir, 0700)
+	if err == nil {
+		err = writeArchive(path, accountId)
+	}
+	if err != nil {
+		log.Println("개인정보 내보내기 생성 실패: ", err)
+		os.Remove(path)
+		if _, err := database.DB.Exec("UPDATE data_exports SET status = ? WHERE id = ?", StatusFailed, id); err != nil {
+			log.Println("내보내기 상태 저장 실패: ", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("UPDATE data_exports SET status = ?, file_path = ?, expires_at = ?, completed_at = ? WHERE id = ?",
+		StatusReady, path, now.Add(archiveTTL), now, id)
+	if err != nil {
+		log.Println("내보내기 상태 저장 실패: ", err)
+	}
+}
+
+// 보관 기간이 지난 압축 파일을 주기적으로 삭제 (서버 시작 시 호출)
+func StartCleanupJob() {
+	go func() {
+		for {
+			if err := cleanup(); err != nil {
+				log.Println("내보내기 파일 정리 실패: ", err)
+			}
+			time.Sleep(cleanupInterval)
+		}
+	}()
+}
+
+func cleanup() error {
+	now := time.Now()
+
+	// 생성 도중 서버가 재시작되어 남은 요청
+	_, err := database.DB.Exec("UPDATE data_exports SET status = ? WHERE status = ? AND created_at < ?", StatusFailed, StatusPending, now.Add(-pendingTimeout))
+	if err != nil {
+		return err
+	}
+
+	var expired []dataExport
+	err = database.DB.Select(&expired, selectExport+" WHERE status = ? AND expires_at < ?", StatusReady, now)
+	if err != nil {
+		return err
+	}
+	for _, export := range expired {
+		if err := os.Remove(export.FilePath.String); err != nil && !os.IsNotExist(err) {
+			log.Println("내보내기 파일 삭제 실패: ", err)
+			continue
+		}
+		_, err := database.DB.Exec("UPDATE data_exports SET status = ?, file_path = NULL, download_token_hash = NULL WHERE id = ?", StatusExpired, export.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
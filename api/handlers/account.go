@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"database/sql"
+	"guny-world-backend/api/account"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	passwordapi "guny-world-backend/api/password"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/throttle"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 비밀번호가 없는 계정의 탈퇴에 필요한 최근 로그인 기준
+const recentLoginWindow = time.Minute * 10
+
+// 회원 탈퇴 핸들러
+// 비밀번호가 있는 계정은 현재 비밀번호 확인, 없는 계정(외부 로그인, 패스키)은 최근에 로그인한 세션에서만 가능
+// 유예 기간 안에 다시 로그인하면 복구
+func DeleteAccount(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	type RequestQuery struct {
+		Password string `json:"password"`
+	}
+
+	var requestQuery RequestQuery
+	_ = c.BodyParser(&requestQuery)
+
+	var password sql.NullString
+	err = database.DB.Get(&password, "SELECT password FROM accounts WHERE id = ?", principal.UserId)
+	if err != nil {
+		log.Println("데이터베이스 조회 에러: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+	if password.Valid {
+		// 비밀번호 변경과 같은 실패 기록으로 대입 방지
+		attempt, retryAfter, err := throttle.Default().Begin(passwordapi.AttemptKey(principal.UserId), c.IP())
+		if err != nil {
+			log.Println("비밀번호 실패 기록 조회 에러: ", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		}
+		if retryAfter > 0 {
+			return throttle.Reject(c, retryAfter)
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(password.String), []byte(requestQuery.Password)); err != nil {
+			attempt.Fail()
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "비밀번호가 일치하지 않습니다."})
+		}
+		if err := attempt.Succeed(); err != nil {
+			log.Println("비밀번호 실패 기록 초기화 에러: ", err)
+		}
+	} else {
+		// 탈취한 엑세스 토큰만으로 탈퇴시키지 못하도록, 재발급과 관계없이 로그인한 시각으로 확인
+		startedAt, err := session.StartedAt(principal.UserId, principal.SessionId)
+		if err != nil && err != session.ErrNotFound {
+			log.Println("세션 조회 에러: ", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+		}
+		if err == session.ErrNotFound || time.Since(startedAt) > recentLoginWindow {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "탈퇴하려면 다시 로그인해 주세요.", "code": "reauthentication_required"})
+		}
+	}
+
+	purgeAt, err := account.Delete(principal.UserId)
+	if err != nil {
+		log.Println("회원 탈퇴 처리 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "탈퇴 요청이 처리되었습니다. 기간 안에 다시 로그인하면 계정이 복구됩니다.",
+		"purgeAt": purgeAt,
+	})
+}
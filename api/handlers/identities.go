@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/identity"
+	"guny-world-backend/api/oauth"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 연결된 로그인 수단 조회 핸들러
+func GetIdentities(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	identities, err := identity.List(principal.UserId)
+	if err != nil {
+		log.Println("연결된 로그인 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	var hasPassword bool
+	err = database.DB.Get(&hasPassword, "SELECT password IS NOT NULL FROM accounts WHERE id = ?", principal.UserId)
+	if err != nil {
+		log.Println("비밀번호 설정 여부 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"identities": identities, "hasPassword": hasPassword})
+}
+
+// 외부 로그인 연결 해제 핸들러
+func DeleteIdentity(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+	provider := c.Params("provider")
+
+	// 연결을 끊은 뒤 제공자에도 폐기 요청할 토큰
+	_, tokens, err := identity.LoadTokens(principal.UserId, provider)
+	if err != nil && err != identity.ErrNotFound {
+		log.Println("외부 로그인 토큰 조회 실패: ", err)
+	}
+
+	err = identity.Unlink(principal.UserId, provider)
+	if err == identity.ErrNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Identity not found"})
+	} else if err == identity.ErrLastLoginMethod {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("외부 로그인 연결 해제 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	if err := oauth.RevokeTokens(provider, tokens); err != nil && err != oauth.ErrNoToken {
+		log.Println("외부 로그인 토큰 폐기 실패: ", provider, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "로그인 연결이 해제되었습니다."})
+}
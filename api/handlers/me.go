@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"database/sql"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/avatar"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/identity"
+	"guny-world-backend/api/register"
+	"guny-world-backend/api/role"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 이름 최대 길이
+const maxNameLength = 30
+
+// 내 프로필 조회 핸들러
+func GetMe(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	var account struct {
+		Id           int64          `db:"id"`
+		Email        sql.NullString `db:"email"`
+		Nickname     string         `db:"nickname"`
+		Name         sql.NullString `db:"name"`
+		ProfileImage sql.NullString `db:"profile_image"`
+		AvatarKey    sql.NullString `db:"avatar_key"`
+		HasPassword  bool           `db:"has_password"`
+		CreatedAt    time.Time      `db:"created_at"`
+	}
+	err = database.DB.Get(&account, "SELECT id, email, nickname, name, profile_image, avatar_key, password IS NOT NULL AS has_password, created_at FROM accounts WHERE id = ?", principal.UserId)
+	if err == sql.ErrNoRows {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "User not found"})
+	} else if err != nil {
+		log.Println("프로필 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	identities, err := identity.List(principal.UserId)
+	if err != nil {
+		log.Println("연결된 로그인 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+	providers := []string{}
+	if account.HasPassword {
+		providers = append(providers, "password")
+	}
+	for _, linked := range identities {
+		providers = append(providers, linked.Provider)
+	}
+
+	roles, err := role.List(principal.UserId)
+	if err != nil {
+		log.Println("역할 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"id":           account.Id,
+		"email":        nullString(account.Email),
+		"nickname":     account.Nickname,
+		"name":         nullString(account.Name),
+		"profileImage": nullString(account.ProfileImage),
+		"thumbnails":   avatar.Thumbnails(account.AvatarKey),
+		"createdAt":    account.CreatedAt,
+		"providers":    providers,
+		"roles":        roles,
+	})
+}
+
+// 내 프로필 수정 핸들러 (보낸 필드만 변경)
+func UpdateMe(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	type RequestQuery struct {
+		Nickname *string `json:"nickname"`
+		Name     *string `json:"name"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+	if requestQuery.Nickname == nil && requestQuery.Name == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "변경할 값을 입력해 주세요."})
+	}
+
+	// 회원가입과 같은 닉네임 정책
+	if requestQuery.Nickname != nil {
+		if err := register.ValidateNickname(*requestQuery.Nickname); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	if requestQuery.Name != nil && utf8.RuneCountInString(*requestQuery.Name) > maxNameLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "이름은 최대 30글자까지 입력할 수 있습니다."})
+	}
+
+	// 빈 이름은 삭제로 처리
+	var name sql.NullString
+	if requestQuery.Name != nil {
+		name = sql.NullString{String: *requestQuery.Name, Valid: *requestQuery.Name != ""}
+	}
+	var nickname sql.NullString
+	if requestQuery.Nickname != nil {
+		nickname = sql.NullString{String: *requestQuery.Nickname, Valid: true}
+	}
+
+	_, err = database.DB.Exec("UPDATE accounts SET nickname = COALESCE(?, nickname), name = IF(?, ?, name), updated_at = ? WHERE id = ?",
+		nickname, requestQuery.Name != nil, name, time.Now(), principal.UserId)
+	if err != nil {
+		log.Println("프로필 수정 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return GetMe(c)
+}
+
+func nullString(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.String
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/session"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 로그인된 기기(세션) 목록 조회 핸들러
+func GetSessions(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	sessions, err := session.List(principal.UserId)
+	if err != nil {
+		log.Println("세션 목록 조회 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"sessions": sessions, "currentSessionId": principal.SessionId})
+}
+
+// 로그인된 기기(세션) 하나 로그아웃 핸들러
+func DeleteSession(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	err = session.Revoke(principal.UserId, c.Params("id"))
+	if err == session.ErrNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Session not found"})
+	} else if err != nil {
+		log.Println("세션 폐기 실패: ", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Database error"})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "해당 기기에서 로그아웃 되었습니다."})
+}
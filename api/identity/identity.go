@@ -0,0 +1,184 @@
+// identity/identity.go
+package identity
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/database"
+	"strconv"
+	"time"
+)
+
+// 외부 로그인 제공자
+const ProviderNaver = "naver"
+
+var (
+	ErrNotFound        = errors.New("연결된 계정이 없습니다")
+	ErrLinkedToOther   = errors.New("이미 다른 계정에 연결된 로그인입니다")
+	ErrProviderLinked  = errors.New("이미 같은 종류의 로그인이 연결되어 있습니다")
+	ErrLastLoginMethod = errors.New("마지막 로그인 수단은 해제할 수 없습니다")
+)
+
+// 계정에 연결된 외부 로그인
+type Identity struct {
+	Provider  string         `db:"provider" json:"provider"`
+	Email     sql.NullString `db:"email" json:"-"`
+	CreatedAt time.Time      `db:"created_at" json:"createdAt"`
+}
+
+// 외부 로그인 사용자 정보
+type Profile struct {
+	Subject string
+	// 예전 방식으로 저장된 식별자 (있으면 처음 로그인할 때 Subject 로 변경)
+	LegacySubject string
+	Email         string
+	Nickname      string
+	Name          string
+	ProfileImage  string
+}
+
+// 외부 로그인으로 계정 ID 찾기
+func Find(provider, subject string) (string, error) {
+	var accountId int64
+	err := database.DB.Get(&accountId, "SELECT account_id FROM identities WHERE provider = ? AND provider_subject = ?", provider, subject)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(accountId, 10), nil
+}
+
+// 외부 로그인 사용자 정보로 계정 ID 찾기 (예전 식별자로 저장된 연결이면 새 식별자로 변경)
+func Resolve(provider string, profile Profile) (string, error) {
+	accountId, err := Find(provider, profile.Subject)
+	if err != ErrNotFound || profile.LegacySubject == "" {
+		return accountId, err
+	}
+
+	result, err := database.DB.Exec("UPDATE identities SET provider_subject = ?, updated_at = ? WHERE provider = ? AND provider_subject = ?",
+		profile.Subject, time.Now(), provider, profile.LegacySubject)
+	if err != nil {
+		return "", err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return "", err
+	} else if affected == 0 {
+		return "", ErrNotFound
+	}
+	return Find(provider, profile.Subject)
+}
+
+// 외부 로그인으로 처음 들어온 사용자의 계정과 연결을 함께 생성
+func CreateAccount(provider string, profile Profile) (string, error) {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec("INSERT INTO accounts (nickname, profile_image, name, created_at) VALUES (?, ?, ?, ?)",
+		profile.Nickname, profile.ProfileImage, profile.Name, now)
+	if err != nil {
+		return "", err
+	}
+	accountId, err := result.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = tx.Exec("INSERT INTO identities (account_id, provider, provider_subject, email, created_at) VALUES (?, ?, ?, ?, ?)",
+		accountId, provider, profile.Subject, nullable(profile.Email), now)
+	if err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(accountId, 10), nil
+}
+
+// 로그인할 때마다 외부 계정의 이메일 갱신
+func Touch(provider string, profile Profile) error {
+	_, err := database.DB.Exec("UPDATE identities SET email = ?, updated_at = ? WHERE provider = ? AND provider_subject = ?",
+		nullable(profile.Email), time.Now(), provider, profile.Subject)
+	return err
+}
+
+// 기존 계정에 외부 로그인 연결
+func Link(accountId, provider string, profile Profile) error {
+	owner, err := Resolve(provider, profile)
+	if err == nil {
+		if owner == accountId {
+			return nil
+		}
+		return ErrLinkedToOther
+	} else if err != ErrNotFound {
+		return err
+	}
+
+	var count int
+	err = database.DB.Get(&count, "SELECT COUNT(*) FROM identities WHERE account_id = ? AND provider = ?", accountId, provider)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrProviderLinked
+	}
+
+	_, err = database.DB.Exec("INSERT INTO identities (account_id, provider, provider_subject, email, created_at) VALUES (?, ?, ?, ?, ?)",
+		accountId, provider, profile.Subject, nullable(profile.Email), time.Now())
+	return err
+}
+
+// 외부 로그인 연결 해제 (비밀번호, 다른 외부 로그인, 패스키 중 하나는 남아 있어야 함)
+func Unlink(accountId, provider string) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var linked int
+	err = tx.Get(&linked, "SELECT COUNT(*) FROM identities WHERE account_id = ? AND provider = ? FOR UPDATE", accountId, provider)
+	if err != nil {
+		return err
+	}
+	if linked == 0 {
+		return ErrNotFound
+	}
+
+	var remaining struct {
+		Password   int `db:"password"`
+		Identities int `db:"identities"`
+		Passkeys   int `db:"passkeys"`
+	}
+	err = tx.Get(&remaining, `SELECT
+		(SELECT COUNT(*) FROM accounts WHERE id = ? AND password IS NOT NULL) AS password,
+		(SELECT COUNT(*) FROM identities WHERE account_id = ? AND provider <> ?) AS identities,
+		(SELECT COUNT(*) FROM webauthn_credentials WHERE user_id = ?) AS passkeys`, accountId, accountId, provider, accountId)
+	if err != nil {
+		return err
+	}
+	if remaining.Password+remaining.Identities+remaining.Passkeys == 0 {
+		return ErrLastLoginMethod
+	}
+
+	if _, err = tx.Exec("DELETE FROM identities WHERE account_id = ? AND provider = ?", accountId, provider); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 계정에 연결된 외부 로그인 목록
+func List(accountId string) ([]Identity, error) {
+	identities := []Identity{}
+	err := database.DB.Select(&identities, "SELECT provider, email, created_at FROM identities WHERE account_id = ? ORDER BY created_at", accountId)
+	return identities, err
+}
+
+func nullable(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
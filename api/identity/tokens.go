@@ -0,0 +1,84 @@
+// identity/tokens.go
+package identity
+
+import (
+	"database/sql"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/secretbox"
+	"time"
+)
+
+// 외부 로그인 제공자가 발급한 토큰 (DB 에는 암호화해서 저장)
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time // 알 수 없으면 zero
+}
+
+// 연결된 외부 계정의 토큰 저장 (새 리프레시 토큰이 없으면 기존 값 유지)
+func SaveTokens(provider, subject string, tokens Tokens) error {
+	accessToken, err := seal(tokens.AccessToken)
+	if err != nil {
+		return err
+	}
+	refreshToken, err := seal(tokens.RefreshToken)
+	if err != nil {
+		return err
+	}
+	var expiresAt sql.NullTime
+	if !tokens.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: tokens.ExpiresAt, Valid: true}
+	}
+
+	_, err = database.DB.Exec(`UPDATE identities SET access_token = ?, refresh_token = COALESCE(?, refresh_token), token_expires_at = ?, updated_at = ?
+		WHERE provider = ? AND provider_subject = ?`,
+		accessToken, refreshToken, expiresAt, time.Now(), provider, subject)
+	return err
+}
+
+// 계정에 연결된 외부 계정의 식별자와 토큰 조회
+func LoadTokens(accountId, provider string) (subject string, tokens Tokens, err error) {
+	var row struct {
+		Subject        string         `db:"provider_subject"`
+		AccessToken    sql.NullString `db:"access_token"`
+		RefreshToken   sql.NullString `db:"refresh_token"`
+		TokenExpiresAt sql.NullTime   `db:"token_expires_at"`
+	}
+	err = database.DB.Get(&row, "SELECT provider_subject, access_token, refresh_token, token_expires_at FROM identities WHERE account_id = ? AND provider = ?", accountId, provider)
+	if err == sql.ErrNoRows {
+		return "", Tokens{}, ErrNotFound
+	} else if err != nil {
+		return "", Tokens{}, err
+	}
+
+	if tokens.AccessToken, err = open(row.AccessToken); err != nil {
+		return "", Tokens{}, err
+	}
+	if tokens.RefreshToken, err = open(row.RefreshToken); err != nil {
+		return "", Tokens{}, err
+	}
+	tokens.ExpiresAt = row.TokenExpiresAt.Time
+	return row.Subject, tokens, nil
+}
+
+func seal(value string) (sql.NullString, error) {
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+	sealed, err := secretbox.Seal([]byte(value))
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: sealed, Valid: true}, nil
+}
+
+func open(value sql.NullString) (string, error) {
+	if !value.Valid {
+		return "", nil
+	}
+	plaintext, err := secretbox.Open(value.String)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
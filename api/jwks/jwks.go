@@ -0,0 +1,19 @@
+// jwks/jwks.go
+package jwks
+
+import (
+	"guny-world-backend/api/token"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 토큰 검증용 공개 키 목록 핸들러 (게임 서버 등 외부 서비스가 kid 로 키를 찾아 RS256 검증)
+func JWKS(c *fiber.Ctx) error {
+	keys := []token.JWK{}
+	if keySet := token.Default().Config.Keys; keySet != nil {
+		keys = keySet.JWKS()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"keys": keys})
+}
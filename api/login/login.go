@@ -3,15 +3,16 @@ package login
 
 import (
 	"database/sql"
-	"encoding/json"
+	"guny-world-backend/api/account"
 	"guny-world-backend/api/database"
+	"guny-world-backend/api/role"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/suspension"
+	"guny-world-backend/api/throttle"
+	"guny-world-backend/api/token"
+	"guny-world-backend/api/twofactor"
 	"log"
-	"net/http"
-	"net/url"
-	"os"
-	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -31,216 +32,148 @@ func Login(c *fiber.Ctx) (err error) {
         return c.Status(400).JSON(fiber.Map{"error": "값을 입력해 주세요."})
     }
 
+    // 실패가 누적된 계정/IP 는 잠시 차단
+    attempt, retryAfter, err := throttle.Default().Begin(requestQuery.UserId, c.IP())
+    if err != nil {
+        log.Println("로그인 실패 기록 조회 에러: ", err)
+        return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+    }
+    if retryAfter > 0 {
+        return throttle.Reject(c, retryAfter)
+    }
+
     // 유저 아이디의 대한 비번 정보 가져오기
-    var password string
-    err = db.Get(&password, "SELECT password FROM users WHERE user_id = ?", requestQuery.UserId)
+    var credential struct {
+        Id       string `db:"id"`
+        Password string `db:"password"`
+    }
+    err = db.Get(&credential, "SELECT id, password FROM accounts WHERE email = ? AND password IS NOT NULL", requestQuery.UserId)
     if err != nil {
         log.Println("데이터베이스 조회 에러: ", err)
+        if err == sql.ErrNoRows {
+            // 없는 계정도 실패로 세지만 알림 메일은 보내지 않음
+            attempt.Fail()
+        } else {
+            cancelAttempt(attempt)
+        }
         return c.Status(500).JSON(fiber.Map{"error": "유저 정보를 찾을 수 없습니다."})
     }
 
     // 비밀번호 검증
-    if err := bcrypt.CompareHashAndPassword([]byte(password), []byte(requestQuery.Password)); err != nil {
+    if err := bcrypt.CompareHashAndPassword([]byte(credential.Password), []byte(requestQuery.Password)); err != nil {
         log.Println("비밀번호 검증 실패: ", err)
+        if attempt.Fail() {
+            notifyLockout(credential.Id)
+        }
         return c.Status(500).JSON(fiber.Map{"error": "비밀번호가 일치하지 않습니다."})
     }
 
-    // JWT 키 불러오기
-	jwtSecret := os.Getenv("JWT_SECRET_TOKEN")
-    if jwtSecret == "" {
-        log.Println("JWT 시크릿 키가 설정되지 않았습니다.")
-        return c.Status(500).JSON(fiber.Map{"error": "서버 설정 오류입니다. 관리자에게 문의하세요."})
+    if err := attempt.Succeed(); err != nil {
+        log.Println("로그인 실패 기록 초기화 에러: ", err)
     }
 
-    // 해당 유저의 id 가져오기
-    var id string
-    err = db.Get(&id, "SELECT id FROM users WHERE user_id = ?", requestQuery.UserId)
+    // 해당 유저의 id, 이메일 인증 여부 가져오기
+    var user struct {
+        Id              string       `db:"id"`
+        EmailVerifiedAt sql.NullTime `db:"email_verified_at"`
+    }
+    err = db.Get(&user, "SELECT id, email_verified_at FROM accounts WHERE email = ?", requestQuery.UserId)
     if err != nil {
         log.Println("데이터베이스 조회 에러: ", err)
         return c.Status(500).JSON(fiber.Map{"error": "유저 정보를 가져오는 데 실패했습니다."})
     }
+    id := user.Id
+
+    // 이메일 인증 전에는 로그인 불가
+    if !user.EmailVerifiedAt.Valid {
+        return c.Status(403).JSON(fiber.Map{"error": "이메일 인증 후 로그인할 수 있습니다.", "code": "email_not_verified"})
+    }
 
-    // 엑세스 토큰 생성
-    accessToken, err := makeAccessToken(id, jwtSecret)
+    // 정지된 계정은 로그인 불가
+    if done, err := rejectSuspended(c, id); done {
+        return err
+    }
+
+    // 2단계 인증 사용 중이면 인증 코드 확인 후 토큰 발급
+    return completeLogin(c, id, session.MethodPassword)
+}
+
+// 정지된 계정이면 사유와 종료일을 응답 (응답했으면 true)
+func rejectSuspended(c *fiber.Ctx, userId string) (bool, error) {
+    s, err := suspension.Get(userId)
     if err != nil {
-        log.Println("엑세스 토큰 생성 실패: ", err)
-        return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
+        log.Println("계정 정지 조회 에러: ", err)
+        return true, c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+    }
+    if s != nil {
+        return true, suspension.Reject(c, s)
     }
+    return false, nil
+}
 
-    // 리프레시 토큰 생성
-    refreshToken, err := makeRefreshToken(id, jwtSecret)
+// 1단계 로그인(비밀번호, 외부 로그인, 패스키)을 마친 계정의 로그인 응답
+// 2단계 인증을 켠 계정이면 어떤 방식으로 로그인했든 대기 토큰만 주고, 인증 코드 확인 후 토큰 발급
+func completeLogin(c *fiber.Ctx, userId string, loginMethod string) error {
+    twoFactorEnabled, err := twofactor.Enabled(userId)
     if err != nil {
-        log.Println("리프레시 토큰 생성 실패: ", err)
+        log.Println("2단계 인증 조회 에러: ", err)
+        return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+    }
+    if twoFactorEnabled {
+        twoFactorToken, err := token.Default().NewTwoFactorToken(userId, loginMethod)
+        if err != nil {
+            log.Println("2단계 인증 토큰 생성 실패: ", err)
+            return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
+        }
+        return c.Status(200).JSON(fiber.Map{"message": "2단계 인증 코드를 입력해 주세요.", "twoFactorRequired": true, "twoFactorToken": twoFactorToken})
+    }
+
+    // 엑세스/리프레시 토큰 발급
+    accessToken, refreshToken, err := issueTokens(c, userId, loginMethod)
+    if err != nil {
+        log.Println("토큰 발급 실패: ", err)
         return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
     }
 
     return c.Status(200).JSON(fiber.Map{"message": "로그인 성공!", "accessToken": accessToken, "refreshToken": refreshToken})
 }
 
-type Claims struct {
-    UserId string `json:"user_id"`
-    jwt.StandardClaims
-}
-
-func makeAccessToken(userId string, jwtSecret string) (accessToken string, err error) {
-    claims := Claims{
-        UserId: userId,
-        StandardClaims: jwt.StandardClaims{
-            ExpiresAt: time.Now().Add(time.Hour * 1).Unix(),
-            Issuer:    "flexible-quest",
-        },
+// 새 세션을 만들고 엑세스/리프레시 토큰 발급 후 리프레시 토큰 저장
+// 2단계 인증 확인이 필요하므로 completeLogin 과 VerifyTwoFactor 에서만 호출
+// 탈퇴 유예 기간 중인 계정이면 로그인과 함께 탈퇴 취소
+func issueTokens(c *fiber.Ctx, userId string, loginMethod string) (accessToken string, refreshToken string, err error) {
+    restored, err := account.Restore(userId)
+    if err != nil {
+        return "", "", err
+    }
+    if restored {
+        log.Println("탈퇴 유예 중인 계정 복구: ", userId)
     }
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    accessToken, err = token.SignedString([]byte(jwtSecret))
+    familyId, err := session.Start(userId, loginMethod, c.Get("User-Agent"), c.IP())
     if err != nil {
-        return "", err
+        return "", "", err
     }
 
-    return accessToken, nil
-}
+    roles, err := role.List(userId)
+    if err != nil {
+        return "", "", err
+    }
 
-func makeRefreshToken(userId string, jwtSecret string) (refreshToken string, err error) {
-    claims := Claims{
-        UserId: userId,
-        StandardClaims: jwt.StandardClaims{
-            ExpiresAt: time.Now().Add(time.Hour * 24 * 3).Unix(),
-            Issuer:    "flexible-quest",
-        },
+    tokens := token.Default()
+    accessToken, err = tokens.NewAccessToken(userId, familyId, roles)
+    if err != nil {
+        return "", "", err
     }
 
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    refreshToken, err = token.SignedString([]byte(jwtSecret))
+    refreshToken, expiresAt, err := tokens.NewRefreshToken(userId, familyId)
     if err != nil {
-        return "", err
+        return "", "", err
     }
 
-    return refreshToken, nil
-}
+    if err = session.Save(userId, familyId, refreshToken, expiresAt); err != nil {
+        return "", "", err
+    }
 
-func NaverLogin(c *fiber.Ctx) error {
-	db := database.DB
-
-	// 네이버에서 전달된 code와 state 파라미터를 가져옵니다.
-	code := c.Query("code")
-	state := c.Query("state")
-
-	// 네이버 Client ID와 Client Secret을 환경변수에서 가져옵니다.
-	clientID := os.Getenv("NAVER_CLIENT_ID")
-	clientSecret := os.Getenv("NAVER_CLIENT_SECRET")
-	redirectURI := "https://game.gunynote.com/naver/callback"
-
-	// 액세스 토큰을 요청하기 위한 URL을 만듭니다.
-	tokenURL := "https://nid.naver.com/oauth2.0/token"
-	data := url.Values{}
-	data.Set("grant_type", "authorization_code")
-	data.Set("client_id", clientID)
-	data.Set("client_secret", clientSecret)
-	data.Set("code", code)
-	data.Set("state", state)
-	data.Set("redirect_uri", redirectURI)
-
-	// 네이버에 액세스 토큰 요청
-	resp, err := http.PostForm(tokenURL, data)
-	if err != nil {
-		log.Println("Error during token request:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to request token from Naver"})
-	}
-	defer resp.Body.Close()
-
-	// 응답을 파싱하여 액세스 토큰을 가져옵니다.
-	var tokenResponse struct {
-		AccessToken     string `json:"access_token"`
-		TokenType       string `json:"token_type"`
-		ExpiresIn       string `json:"expires_in"`
-		RefreshToken    string `json:"refresh_token"`
-		Error           string `json:"error"`
-		ErrorDescription string `json:"error_description"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-		log.Println("Error parsing token response:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse token response from Naver"})
-	}
-
-	if tokenResponse.Error != "" {
-		log.Println("Naver token error:", tokenResponse.Error, tokenResponse.ErrorDescription)
-		return c.Status(500).JSON(fiber.Map{"error": tokenResponse.ErrorDescription})
-	}
-
-	// 네이버 사용자 정보를 가져오기 위한 요청 설정
-	userInfoURL := "https://openapi.naver.com/v1/nid/me"
-	req, err := http.NewRequest("GET", userInfoURL, nil)
-	if err != nil {
-		log.Println("Error creating request for user info:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create request for user info"})
-	}
-	req.Header.Add("Authorization", "Bearer "+tokenResponse.AccessToken)
-
-	client := &http.Client{}
-	userInfoResp, err := client.Do(req)
-	if err != nil {
-		log.Println("Error requesting user info:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to request user info from Naver"})
-	}
-	defer userInfoResp.Body.Close()
-
-	var userInfo struct {
-		Response struct {
-			Id          string `json:"id"`
-			Nickname    string `json:"nickname"`
-			Email       string `json:"email"`
-			Name        string `json:"name"`
-			ProfileImage string `json:"profile_image"`
-		} `json:"response"`
-	}
-
-	if err := json.NewDecoder(userInfoResp.Body).Decode(&userInfo); err != nil {
-		log.Println("Error parsing user info response:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse user info response from Naver"})
-	}
-
-	// 이메일 주소를 user_id로 사용하여 네이버 사용자 정보를 데이터베이스에 저장하거나 갱신
-	var existingUserID string
-	err = db.Get(&existingUserID, "SELECT user_id FROM naver_user_info WHERE user_id = ?", userInfo.Response.Email)
-	if err != nil && err != sql.ErrNoRows {
-		// 데이터베이스 오류 처리
-		log.Println("Error fetching user:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Database error"})
-	}
-
-	if existingUserID == "" {
-		// 새 사용자라면 정보를 데이터베이스에 추가
-		_, err = db.Exec("INSERT INTO naver_user_info (user_id, nickname, profile_image, name, created_at) VALUES (?, ?, ?, ?, ?)",
-			userInfo.Response.Email, userInfo.Response.Nickname, userInfo.Response.ProfileImage, userInfo.Response.Name, time.Now())
-		if err != nil {
-			log.Println("Error inserting new user:", err)
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to insert new user into database"})
-		}
-	} else {
-		// 기존 사용자라면 정보를 업데이트
-		_, err = db.Exec("UPDATE naver_user_info SET nickname=?, profile_image=?, name=?, updated_at=? WHERE user_id=?",
-			userInfo.Response.Nickname, userInfo.Response.ProfileImage, userInfo.Response.Name, time.Now(), userInfo.Response.Email)
-		if err != nil {
-			log.Println("Error updating existing user:", err)
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to update user info in database"})
-		}
-	}
-
-	// 사용자에게 JWT 발급
-	jwtSecret := os.Getenv("JWT_SECRET_TOKEN")
-	accessToken, err := makeAccessToken(userInfo.Response.Email, jwtSecret)
-	if err != nil {
-		log.Println("Error creating access token:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create access token"})
-	}
-
-	refreshToken, err := makeRefreshToken(userInfo.Response.Email, jwtSecret)
-	if err != nil {
-		log.Println("Error creating refresh token:", err)
-		return c.Status(500).JSON(fiber.Map{"error": "Failed to create refresh token"})
-	}
-
-	// 클라이언트에게 JWT 토큰 반환
-	return c.Status(200).JSON(fiber.Map{"accessToken": accessToken, "refreshToken": refreshToken})
+    return accessToken, refreshToken, nil
 }
@@ -0,0 +1,269 @@
+// login/oauth.go
+package login
+
+import (
+	"errors"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/identity"
+	"guny-world-backend/api/oauth"
+	"guny-world-backend/api/onetime"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 로그인을 시작한 브라우저를 구분하는 쿠키
+const bindingCookie = "oauth_binding"
+
+// 프론트엔드가 만드는 nonce 길이 제한
+const (
+	minNonceLength = 16
+	maxNonceLength = 128
+)
+
+// 외부 로그인 시작 (제공자 로그인 화면으로 이동, reprompt=1 이면 동의 화면을 다시 표시)
+// 프론트엔드는 무작위 nonce 를 만들어 보관한 뒤 nonce 쿼리로 넘기고, OAuthExchange 에서 같은 값을 보내야 함
+func OAuthAuthorize(c *fiber.Ctx) error {
+	nonce := c.Query("nonce")
+	if len(nonce) < minNonceLength || len(nonce) > maxNonceLength {
+		return c.Status(400).JSON(fiber.Map{"error": "nonce 값이 올바르지 않습니다."})
+	}
+
+	authURL, err := startOAuth(c, c.Params("provider"), "", nonce)
+	if err == oauth.ErrUnknownProvider || err == oauth.ErrNotConfigured {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("외부 로그인 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// 로그인한 계정에 외부 로그인 연결 시작 (브라우저가 이동할 주소 반환)
+// XHR 응답으로는 교차 출처 프론트엔드에 연결 확인 쿠키를 심을 수 없으므로,
+// 1회용 티켓이 담긴 LinkIdentityStart 주소를 주고 브라우저가 직접 이동하게 함
+func LinkIdentityBegin(c *fiber.Ctx) error {
+	principal := auth.GetPrincipal(c)
+	providerName := c.Params("provider")
+
+	if _, err := oauth.Get(providerName); err == oauth.ErrUnknownProvider || err == oauth.ErrNotConfigured {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	ticket, err := oauth.NewLinkTicket(principal.UserId, providerName)
+	if err != nil {
+		log.Println("외부 로그인 연결 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	query := url.Values{"ticket": {ticket}}
+	if c.Query("reprompt") == "1" {
+		query.Set("reprompt", "1")
+	}
+	return c.Status(200).JSON(fiber.Map{"url": c.BaseURL() + "/api/oauth/" + url.PathEscape(providerName) + "/link?" + query.Encode()})
+}
+
+// 연결 티켓을 확인하고 제공자 로그인 화면으로 이동 (브라우저 이동으로 호출되므로 쿠키가 저장됨)
+func LinkIdentityStart(c *fiber.Ctx) error {
+	providerName := c.Params("provider")
+
+	accountId, err := oauth.ConsumeLinkTicket(c.Query("ticket"), providerName)
+	if err == oauth.ErrLinkTicketInvalid {
+		return redirectFrontend(c, url.Values{"error": {"invalid_link"}})
+	} else if err != nil {
+		log.Println("외부 로그인 연결 티켓 확인 실패: ", err)
+		return redirectFrontend(c, url.Values{"error": {"server_error"}})
+	}
+
+	authURL, err := startOAuth(c, providerName, accountId, "")
+	if err == oauth.ErrUnknownProvider || err == oauth.ErrNotConfigured {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("외부 로그인 연결 시작 실패: ", err)
+		return redirectFrontend(c, url.Values{"error": {"server_error"}})
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// 네이버 콜백 (기존 주소 유지)
+func NaverCallback(c *fiber.Ctx) error {
+	return oauthCallback(c, oauth.Naver)
+}
+
+// 외부 로그인 제공자 콜백
+func OAuthCallback(c *fiber.Ctx) error {
+	return oauthCallback(c, c.Params("provider"))
+}
+
+// 로그인(또는 계정 연결)을 마치고 프론트엔드로 이동
+// 로그인이면 토큰 대신 1회용 코드를 넘기고, 프론트엔드가 OAuthExchange 로 토큰을 받음
+func oauthCallback(c *fiber.Ctx, providerName string) error {
+	provider, err := oauth.Get(providerName)
+	if err == oauth.ErrUnknownProvider || err == oauth.ErrNotConfigured {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// 이 브라우저에서 시작한 요청인지 확인
+	started, err := consumeState(c, providerName, c.Query("state"))
+	if err != nil {
+		if err != oauth.ErrStateMissing && err != oauth.ErrStateInvalid && err != oauth.ErrStateUsed && err != oauth.ErrStateExpired {
+			log.Println("외부 로그인 state 확인 실패: ", err)
+			return redirectFrontend(c, url.Values{"error": {"server_error"}})
+		}
+		return redirectFrontend(c, url.Values{"error": {"invalid_state"}})
+	}
+
+	// 사용자가 동의를 취소하는 등 제공자가 오류로 돌려보낸 경우
+	if c.Query("error") != "" || c.Query("code") == "" {
+		return redirectFrontend(c, url.Values{"error": {"access_denied"}})
+	}
+
+	// 인가 코드로 사용자 정보 조회
+	profile, token, err := fetchProfile(provider, c.Query("code"), c.Query("state"), started.CodeVerifier)
+	if errors.Is(err, oauth.ErrMissingScope) {
+		// 프론트엔드에서 안내 후 reprompt=1 로 다시 시작
+		log.Println("외부 로그인 필수 동의 항목 누락: ", err)
+		return redirectFrontend(c, url.Values{"error": {"missing_scope"}, "provider": {providerName}})
+	} else if err != nil {
+		log.Println("외부 로그인 사용자 정보 조회 실패: ", providerName, err)
+		return redirectFrontend(c, url.Values{"error": {"provider_error"}})
+	}
+
+	// 계정 연결
+	if started.AccountId != "" {
+		err = identity.Link(started.AccountId, providerName, profile)
+		if err == identity.ErrLinkedToOther || err == identity.ErrProviderLinked {
+			return redirectFrontend(c, url.Values{"error": {"already_linked"}})
+		} else if err != nil {
+			log.Println("외부 로그인 연결 실패: ", err)
+			return redirectFrontend(c, url.Values{"error": {"server_error"}})
+		}
+		storeTokens(providerName, profile.Subject, token)
+		return redirectFrontend(c, url.Values{"linked": {providerName}})
+	}
+
+	// 연결된 계정이 있으면 그 계정으로, 없으면 새 계정을 만들어 로그인
+	accountId, err := identity.Resolve(providerName, profile)
+	if err == identity.ErrNotFound {
+		accountId, err = identity.CreateAccount(providerName, profile)
+		if err != nil {
+			log.Println("외부 로그인 계정 생성 실패: ", err)
+			return redirectFrontend(c, url.Values{"error": {"server_error"}})
+		}
+	} else if err != nil {
+		log.Println("외부 로그인 연결 조회 실패: ", err)
+		return redirectFrontend(c, url.Values{"error": {"server_error"}})
+	} else if err := identity.Touch(providerName, profile); err != nil {
+		log.Println("외부 로그인 연결 갱신 실패: ", err)
+	}
+	storeTokens(providerName, profile.Subject, token)
+
+	code, err := oauth.NewHandoff(accountId, providerName, started.NonceHash)
+	if err != nil {
+		log.Println("로그인 코드 생성 실패: ", err)
+		return redirectFrontend(c, url.Values{"error": {"server_error"}})
+	}
+	return redirectFrontend(c, url.Values{"code": {code}})
+}
+
+// 콜백에서 받은 1회용 코드를 토큰으로 교환
+// 로그인을 시작할 때 넘긴 nonce 를 함께 받아, 다른 사람이 넘긴 코드로 로그인되지 않도록 확인
+func OAuthExchange(c *fiber.Ctx) error {
+	type RequestQuery struct {
+		Code  string `json:"code"`
+		Nonce string `json:"nonce"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.Code == "" || requestQuery.Nonce == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "값을 입력해 주세요."})
+	}
+
+	accountId, loginMethod, err := oauth.ConsumeHandoff(requestQuery.Code, requestQuery.Nonce)
+	if err == oauth.ErrHandoffInvalid {
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("로그인 코드 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 정지된 계정은 로그인 불가
+	if done, err := rejectSuspended(c, accountId); done {
+		return err
+	}
+
+	// 2단계 인증을 켠 계정은 외부 로그인도 인증 코드 확인 후 토큰 발급
+	return completeLogin(c, accountId, loginMethod)
+}
+
+// state 를 발급하고 제공자 인가 주소 생성
+func startOAuth(c *fiber.Ctx, providerName string, accountId string, nonce string) (string, error) {
+	provider, err := oauth.Get(providerName)
+	if err != nil {
+		return "", err
+	}
+
+	// 브라우저마다 하나의 값을 쓰고, 없으면 새로 발급
+	binding := c.Cookies(bindingCookie)
+	if binding == "" {
+		binding, _, err = onetime.New()
+		if err != nil {
+			return "", err
+		}
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     bindingCookie,
+		Value:    binding,
+		Path:     "/api",
+		Expires:  time.Now().Add(time.Hour),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: "Lax",
+	})
+
+	state, verifier, err := oauth.NewState(providerName, accountId, binding, nonce)
+	if err != nil {
+		return "", err
+	}
+	return provider.AuthCodeURL(state, verifier, c.Query("reprompt") == "1"), nil
+}
+
+func consumeState(c *fiber.Ctx, providerName string, state string) (*oauth.State, error) {
+	return oauth.ConsumeState(providerName, state, c.Cookies(bindingCookie))
+}
+
+// 외부 로그인 결과를 가지고 프론트엔드로 이동
+// OAUTH_FRONTEND_REDIRECT (기본값 FRONTEND_URL/oauth/callback)
+func redirectFrontend(c *fiber.Ctx, query url.Values) error {
+	target := os.Getenv("OAUTH_FRONTEND_REDIRECT")
+	if target == "" {
+		target = os.Getenv("FRONTEND_URL") + "/oauth/callback"
+	}
+	return c.Redirect(target+"?"+query.Encode(), fiber.StatusFound)
+}
+
+// 인가 코드를 토큰으로 교환한 뒤 사용자 정보 조회
+func fetchProfile(provider oauth.Provider, code string, state string, verifier string) (identity.Profile, *oauth.Token, error) {
+	token, err := provider.Exchange(code, state, verifier)
+	if err != nil {
+		return identity.Profile{}, nil, err
+	}
+	profile, err := provider.Profile(token)
+	if err != nil {
+		return identity.Profile{}, nil, err
+	}
+	return profile, token, nil
+}
+
+// 연결 해제/탈퇴 때 폐기할 수 있도록 제공자 토큰 저장 (실패해도 로그인은 진행)
+func storeTokens(providerName string, subject string, token *oauth.Token) {
+	if err := oauth.StoreTokens(providerName, subject, token); err != nil {
+		log.Println("외부 로그인 토큰 저장 실패: ", err)
+	}
+}
@@ -0,0 +1,55 @@
+// login/passkey.go
+package login
+
+import (
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/webauthn"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// 패스키 로그인 시작 요청 제한 (인증 없이 챌린지를 저장하므로 IP 당 1분에 10회)
+var PasskeyBeginLimit = limiter.New(limiter.Config{
+	Max:        10,
+	Expiration: time.Minute,
+	LimitReached: func(c *fiber.Ctx) error {
+		return c.Status(429).JSON(fiber.Map{"error": "시도 횟수가 너무 많습니다. 잠시 후 다시 시도해 주세요."})
+	},
+})
+
+// 패스키 로그인 시작 핸들러
+// 계정 존재 여부가 드러나지 않도록 사용자를 받지 않고, 기기에 저장된 패스키(discoverable) 중에서 선택
+func PasskeyBegin(c *fiber.Ctx) (err error) {
+	options, err := webauthn.BeginLogin()
+	if err != nil {
+		log.Println("패스키 로그인 옵션 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(options)
+}
+
+// 패스키 로그인 완료 핸들러 (비밀번호 로그인과 같은 토큰 발급)
+func PasskeyFinish(c *fiber.Ctx) (err error) {
+	var response webauthn.AssertionResponse
+	if err := c.BodyParser(&response); err != nil || response.Id == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+
+	userId, err := webauthn.FinishLogin(response)
+	if err != nil {
+		log.Println("패스키 로그인 실패: ", err)
+		return c.Status(401).JSON(fiber.Map{"error": "패스키 인증에 실패했습니다."})
+	}
+
+	// 정지된 계정은 로그인 불가
+	if done, err := rejectSuspended(c, userId); done {
+		return err
+	}
+
+	// 2단계 인증을 켠 계정은 패스키 로그인도 인증 코드 확인 후 토큰 발급
+	return completeLogin(c, userId, session.MethodPasskey)
+}
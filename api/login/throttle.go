@@ -0,0 +1,37 @@
+// login/throttle.go
+package login
+
+import (
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/mail"
+	"guny-world-backend/api/throttle"
+	"log"
+)
+
+// 계정이 잠기면 계정 주인에게 메일로 알림
+// 가입된 계정의 이메일로만 보내고, 요청에 들어온 값으로는 보내지 않음
+func notifyLockout(userId string) {
+	go func() {
+		var email string
+		if err := database.DB.Get(&email, "SELECT email FROM accounts WHERE id = ? AND email IS NOT NULL", userId); err != nil {
+			log.Println("잠금 알림 대상 조회 실패: ", err)
+			return
+		}
+
+		err := mail.Default().Send(mail.Message{
+			To:      email,
+			Subject: "[거니월드] 로그인 시도가 잠시 제한되었습니다",
+			Body:    "회원님의 계정으로 비밀번호가 여러 번 틀린 로그인 시도가 있어 잠시 로그인을 제한했습니다.\n\n본인이 아니라면 비밀번호를 변경해 주세요.",
+		})
+		if err != nil {
+			log.Println("잠금 알림 메일 발송 실패: ", err)
+		}
+	}()
+}
+
+// 실패 기록 취소 (서버 오류로 결과를 판단하지 못한 시도)
+func cancelAttempt(attempt *throttle.Attempt) {
+	if err := attempt.Cancel(); err != nil {
+		log.Println("로그인 실패 기록 취소 에러: ", err)
+	}
+}
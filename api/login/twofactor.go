@@ -0,0 +1,92 @@
+// login/twofactor.go
+package login
+
+import (
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/throttle"
+	"guny-world-backend/api/token"
+	"guny-world-backend/api/twofactor"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 2단계 인증 코드 확인 후 토큰 발급 핸들러
+// 로그인(비밀번호, 외부 로그인, 패스키)이 돌려준 twoFactorToken 과 인증 앱 코드(또는 복구 코드)를 받음
+func VerifyTwoFactor(c *fiber.Ctx) (err error) {
+	type RequestQuery struct {
+		TwoFactorToken string `json:"twoFactorToken"`
+		Code           string `json:"code"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.TwoFactorToken == "" || requestQuery.Code == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "값을 입력해 주세요."})
+	}
+
+	claims, err := token.Default().Parse(requestQuery.TwoFactorToken, token.TypeTwoFactor)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "인증 시간이 만료되었습니다. 다시 로그인해 주세요."})
+	}
+
+	// 이미 사용한 대기 토큰인지 확인
+	used, err := session.IsAccessTokenRevoked(claims.Id, "", claims.UserId, claims.IssuedAt)
+	if err != nil {
+		log.Println("2단계 인증 토큰 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if used {
+		return c.Status(401).JSON(fiber.Map{"error": "인증 시간이 만료되었습니다. 다시 로그인해 주세요."})
+	}
+
+	// 코드 대입 공격 방지 (로그인과 같은 실패 추적기 사용, 잠금 알림 메일은 보내지 않음)
+	attempt, retryAfter, err := throttle.Default().Begin(twofactor.AttemptKey(claims.UserId), c.IP())
+	if err != nil {
+		log.Println("로그인 실패 기록 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if retryAfter > 0 {
+		return throttle.Reject(c, retryAfter)
+	}
+
+	ok, err := twofactor.Verify(claims.UserId, requestQuery.Code)
+	if err != nil {
+		log.Println("2단계 인증 코드 확인 에러: ", err)
+		cancelAttempt(attempt)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if !ok {
+		attempt.Fail()
+		return c.Status(401).JSON(fiber.Map{"error": "인증 코드가 일치하지 않습니다."})
+	}
+
+	if err := attempt.Succeed(); err != nil {
+		log.Println("로그인 실패 기록 초기화 에러: ", err)
+	}
+
+	// 대기 토큰은 한 번만 사용
+	if err := session.RevokeAccessToken(claims.Id, claims.UserId, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		log.Println("2단계 인증 토큰 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 비밀번호 확인 이후 정지되었을 수 있으므로 다시 확인
+	if done, err := rejectSuspended(c, claims.UserId); done {
+		return err
+	}
+
+	// 1단계 로그인 방식으로 세션 생성 (이전 버전 대기 토큰은 비밀번호 로그인)
+	loginMethod := claims.LoginMethod
+	if loginMethod == "" {
+		loginMethod = session.MethodPassword
+	}
+	accessToken, refreshToken, err := issueTokens(c, claims.UserId, loginMethod)
+	if err != nil {
+		log.Println("토큰 발급 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "로그인 성공!", "accessToken": accessToken, "refreshToken": refreshToken})
+}
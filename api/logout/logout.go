@@ -0,0 +1,58 @@
+// logout/logout.go
+package logout
+
+import (
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/token"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 현재 기기 로그아웃 핸들러
+// 리프레시 토큰이 속한 세션을 폐기하고, 엑세스 토큰이 함께 오면 남은 유효 기간 동안 거부되도록 폐기
+func Logout(c *fiber.Ctx) (err error) {
+	type RequestQuery struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.RefreshToken == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "리프레시 토큰 값이 존재하지 않습니다."})
+	}
+
+	// 리프레시 토큰 세션 폐기
+	_, err = session.RevokeByToken(requestQuery.RefreshToken)
+	if err != nil && err != session.ErrNotFound {
+		log.Println("리프레시 토큰 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 엑세스 토큰 폐기 (만료되었거나 없으면 무시)
+	if tokenString := auth.BearerToken(c); tokenString != "" {
+		claims, err := token.Default().Parse(tokenString, token.TypeAccess)
+		if err == nil && claims.Id != "" {
+			if err := session.RevokeAccessToken(claims.Id, claims.UserId, time.Unix(claims.ExpiresAt, 0)); err != nil {
+				log.Println("엑세스 토큰 폐기 실패: ", err)
+				return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+			}
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "로그아웃 되었습니다."})
+}
+
+// 모든 기기 로그아웃 핸들러 (auth.RequireAuth 뒤에서 동작)
+func LogoutAll(c *fiber.Ctx) (err error) {
+	principal := auth.GetPrincipal(c)
+
+	if err := session.RevokeAllForUser(principal.UserId); err != nil {
+		log.Println("전체 세션 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "모든 기기에서 로그아웃 되었습니다."})
+}
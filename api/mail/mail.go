@@ -0,0 +1,149 @@
+// mail/mail.go
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidAddress = errors.New("올바르지 않은 메일 주소입니다")
+
+// 보낼 메일
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// 메일 발송 백엔드
+type Sender interface {
+	Send(msg Message) error
+}
+
+var (
+	defaultSender Sender
+	defaultOnce   sync.Once
+)
+
+// 환경변수 MAIL_BACKEND 에 맞는 발송 백엔드
+//
+//	smtp  SMTP_HOST, SMTP_PORT, SMTP_USER, SMTP_PASS, MAIL_FROM 으로 발송
+//	log   로컬 테스트용, 받는 사람과 제목만 로그로 남기고 MAIL_LOG_DIR 이 있으면 .eml 파일로 저장
+//
+// 인증/재설정 메일이 조용히 사라지지 않도록 MAIL_BACKEND 는 반드시 지정해야 함
+// (APP_ENV=development 일 때만 비워 두면 log 사용)
+func LoadSender() (Sender, error) {
+	switch backend := os.Getenv("MAIL_BACKEND"); backend {
+	case "smtp":
+		sender := &SMTPSender{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USER"),
+			Password: os.Getenv("SMTP_PASS"),
+			From:     os.Getenv("MAIL_FROM"),
+		}
+		if sender.Host == "" || sender.Port == "" || sender.From == "" {
+			return nil, errors.New("MAIL_BACKEND=smtp 에는 SMTP_HOST, SMTP_PORT, MAIL_FROM 이 필요합니다")
+		}
+		return sender, nil
+	case "log":
+		return &LogSender{Dir: os.Getenv("MAIL_LOG_DIR"), From: os.Getenv("MAIL_FROM")}, nil
+	case "":
+		if os.Getenv("APP_ENV") == "development" {
+			return &LogSender{Dir: os.Getenv("MAIL_LOG_DIR"), From: os.Getenv("MAIL_FROM")}, nil
+		}
+		return nil, errors.New("MAIL_BACKEND 가 설정되지 않았습니다 (smtp 또는 log)")
+	default:
+		return nil, fmt.Errorf("알 수 없는 MAIL_BACKEND %q", backend)
+	}
+}
+
+// 환경변수 설정으로 기본 발송 백엔드 초기화 (서버 시작 시 호출, 설정이 없으면 종료)
+func Init() {
+	defaultOnce.Do(func() {
+		sender, err := LoadSender()
+		if err != nil {
+			log.Fatal("메일 설정 로드 실패: ", err)
+		}
+		defaultSender = sender
+	})
+}
+
+// 기본 발송 백엔드
+func Default() Sender {
+	Init()
+	return defaultSender
+}
+
+// SMTP 발송 (서버가 지원하면 STARTTLS 사용)
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	data, err := build(s.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	return smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{msg.To}, data)
+}
+
+// 로컬 테스트용 발송 (실제로 보내지 않고, 본문은 MAIL_LOG_DIR 의 .eml 파일에서 확인)
+type LogSender struct {
+	Dir  string
+	From string
+}
+
+func (s *LogSender) Send(msg Message) error {
+	data, err := build(s.From, msg)
+	if err != nil {
+		return err
+	}
+
+	// 본문에는 인증/재설정 링크가 들어 있으므로 로그에는 남기지 않음
+	log.Printf("메일 발송 (log): to=%s subject=%s", msg.To, msg.Subject)
+	if s.Dir == "" {
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), uuid.NewString())
+	return os.WriteFile(filepath.Join(s.Dir, name), data, 0o600)
+}
+
+// RFC 5322 메시지 생성 (헤더 인젝션 방지를 위해 주소에 개행 금지)
+func build(from string, msg Message) ([]byte, error) {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(from, "\r\n") || !strings.Contains(msg.To, "@") {
+		return nil, ErrInvalidAddress
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,75 @@
+// oauth/handoff.go
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"time"
+)
+
+// 프론트엔드로 넘기는 1회용 코드 유효 기간
+const handoffTTL = time.Minute
+
+var ErrHandoffInvalid = errors.New("만료되었거나 이미 사용된 로그인 코드입니다")
+
+// 콜백에서 로그인을 마친 계정을 1회용 코드로 저장
+// nonceHash 는 로그인을 시작할 때 받은 nonce 의 해시로, 다른 브라우저에 코드를 넘겨 로그인시키는 것을 막음
+func NewHandoff(accountId, loginMethod, nonceHash string) (string, error) {
+	code, hash, err := onetime.New()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("INSERT INTO oauth_handoffs (code_hash, account_id, login_method, nonce_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		hash, accountId, loginMethod, nonceHash, now.Add(handoffTTL), now)
+	if err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+// 1회용 코드를 사용 처리하고 계정 ID 와 로그인 방식 반환
+// 로그인을 시작한 프론트엔드가 보관한 nonce 와 다르면 거부
+func ConsumeHandoff(code, nonce string) (accountId string, loginMethod string, err error) {
+	if code == "" || nonce == "" {
+		return "", "", ErrHandoffInvalid
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	hash := onetime.Hash(code)
+	var row struct {
+		AccountId   string         `db:"account_id"`
+		LoginMethod string         `db:"login_method"`
+		NonceHash   sql.NullString `db:"nonce_hash"`
+		ExpiresAt   time.Time      `db:"expires_at"`
+		UsedAt      sql.NullTime   `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT account_id, login_method, nonce_hash, expires_at, used_at FROM oauth_handoffs WHERE code_hash = ? FOR UPDATE", hash)
+	if err == sql.ErrNoRows {
+		return "", "", ErrHandoffInvalid
+	} else if err != nil {
+		return "", "", err
+	}
+	if row.UsedAt.Valid || time.Now().After(row.ExpiresAt) {
+		return "", "", ErrHandoffInvalid
+	}
+	if !row.NonceHash.Valid || row.NonceHash.String != onetime.Hash(nonce) {
+		return "", "", ErrHandoffInvalid
+	}
+
+	if _, err = tx.Exec("UPDATE oauth_handoffs SET used_at = ? WHERE code_hash = ?", time.Now(), hash); err != nil {
+		return "", "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", "", err
+	}
+	return row.AccountId, row.LoginMethod, nil
+}
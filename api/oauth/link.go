@@ -0,0 +1,70 @@
+// oauth/link.go
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"time"
+)
+
+// 계정 연결 티켓 유효 기간 (발급받은 프론트엔드가 바로 이동)
+const linkTicketTTL = time.Minute
+
+var ErrLinkTicketInvalid = errors.New("만료되었거나 이미 사용된 연결 요청입니다")
+
+// 로그인한 계정의 외부 로그인 연결 티켓 발급
+// 쿠키를 받을 수 없는 XHR 대신, 이 티켓을 들고 브라우저가 직접 이동한 요청에서 연결을 시작
+func NewLinkTicket(accountId, provider string) (string, error) {
+	ticket, hash, err := onetime.New()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("INSERT INTO oauth_link_tickets (ticket_hash, provider, account_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		hash, provider, accountId, now.Add(linkTicketTTL), now)
+	if err != nil {
+		return "", err
+	}
+	return ticket, nil
+}
+
+// 연결 티켓을 사용 처리하고 연결할 계정 ID 반환
+func ConsumeLinkTicket(ticket, provider string) (accountId string, err error) {
+	if ticket == "" {
+		return "", ErrLinkTicketInvalid
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	hash := onetime.Hash(ticket)
+	var row struct {
+		Provider  string       `db:"provider"`
+		AccountId string       `db:"account_id"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT provider, account_id, expires_at, used_at FROM oauth_link_tickets WHERE ticket_hash = ? FOR UPDATE", hash)
+	if err == sql.ErrNoRows {
+		return "", ErrLinkTicketInvalid
+	} else if err != nil {
+		return "", err
+	}
+	if row.Provider != provider || row.UsedAt.Valid || time.Now().After(row.ExpiresAt) {
+		return "", ErrLinkTicketInvalid
+	}
+
+	if _, err = tx.Exec("UPDATE oauth_link_tickets SET used_at = ? WHERE ticket_hash = ?", time.Now(), hash); err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return row.AccountId, nil
+}
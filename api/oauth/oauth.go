@@ -0,0 +1,253 @@
+// oauth/oauth.go
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"guny-world-backend/api/identity"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownProvider = errors.New("지원하지 않는 로그인 제공자입니다")
+	ErrNotConfigured   = errors.New("로그인 제공자 설정이 없습니다")
+	// 사용자가 필수 정보 제공에 동의하지 않음 (다시 동의를 받아야 함)
+	ErrMissingScope = errors.New("필수 정보 제공에 동의하지 않았습니다")
+)
+
+// 외부 로그인 제공자 (인가 URL, 코드 교환, 사용자 정보 조회)
+type Provider interface {
+	Name() string
+	// verifier 는 PKCE code_verifier (PKCE 를 지원하지 않는 제공자는 무시)
+	// reprompt 면 이미 동의한 사용자에게도 동의 화면을 다시 보여줌
+	AuthCodeURL(state string, verifier string, reprompt bool) string
+	Exchange(code, state string, verifier string) (*Token, error)
+	Profile(token *Token) (identity.Profile, error)
+	// 리프레시 토큰으로 엑세스 토큰 재발급
+	Refresh(refreshToken string) (*Token, error)
+	// 제공자에 토큰 폐기 요청 (지원하지 않는 제공자는 아무것도 하지 않음)
+	Revoke(token *Token) error
+}
+
+// 제공자 엔드포인트
+type Endpoint struct {
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+}
+
+// 제공자 설정
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Endpoint     Endpoint
+}
+
+// 제공자가 발급한 토큰
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresIn    time.Duration
+}
+
+// 제공자 오류 응답 (error, error_description)
+type Error struct {
+	Code        string
+	Description string
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// 표준 authorization code 흐름을 따르는 제공자
+// 사용자 정보 응답 형태만 제공자마다 다르므로 mapProfile 로 변환
+type provider struct {
+	name       string
+	config     Config
+	client     *http.Client
+	pkce       bool
+	reprompt   url.Values
+	mapProfile func(body []byte) (identity.Profile, error)
+	revoke     func(p *provider, token *Token) error
+}
+
+func (p *provider) Name() string {
+	return p.name
+}
+
+func (p *provider) AuthCodeURL(state string, verifier string, reprompt bool) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("state", state)
+	if len(p.config.Scopes) > 0 {
+		query.Set("scope", strings.Join(p.config.Scopes, " "))
+	}
+	if p.pkce && verifier != "" {
+		query.Set("code_challenge", codeChallenge(verifier))
+		query.Set("code_challenge_method", "S256")
+	}
+	if reprompt {
+		for key, values := range p.reprompt {
+			query[key] = values
+		}
+	}
+	return withQuery(p.config.Endpoint.AuthURL, query)
+}
+
+func (p *provider) Exchange(code, state string, verifier string) (*Token, error) {
+	data := url.Values{}
+	data.Set("grant_type", "authorization_code")
+	data.Set("client_id", p.config.ClientID)
+	data.Set("client_secret", p.config.ClientSecret)
+	data.Set("code", code)
+	data.Set("redirect_uri", p.config.RedirectURL)
+	if state != "" {
+		data.Set("state", state)
+	}
+	if p.pkce && verifier != "" {
+		data.Set("code_verifier", verifier)
+	}
+	return p.requestToken(data)
+}
+
+func (p *provider) Refresh(refreshToken string) (*Token, error) {
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("client_id", p.config.ClientID)
+	data.Set("client_secret", p.config.ClientSecret)
+	data.Set("refresh_token", refreshToken)
+
+	token, err := p.requestToken(data)
+	if err != nil {
+		return nil, err
+	}
+	// 네이버처럼 새 리프레시 토큰을 주지 않으면 기존 값을 계속 사용
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+func (p *provider) Revoke(token *Token) error {
+	if p.revoke == nil {
+		return nil
+	}
+	return p.revoke(p, token)
+}
+
+func (p *provider) Profile(token *Token) (identity.Profile, error) {
+	req, err := http.NewRequest("GET", p.config.Endpoint.UserInfoURL, nil)
+	if err != nil {
+		return identity.Profile{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return identity.Profile{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return identity.Profile{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return identity.Profile{}, fmt.Errorf("%s 사용자 정보 조회 실패: %d %s", p.name, resp.StatusCode, body)
+	}
+
+	profile, err := p.mapProfile(body)
+	if err != nil {
+		return identity.Profile{}, err
+	}
+	if profile.Subject == "" {
+		return identity.Profile{}, fmt.Errorf("%s 사용자 정보에 식별자가 없습니다", p.name)
+	}
+	// 닉네임은 계정을 만들 때 필요하므로 동의하지 않았으면 다시 요청
+	if profile.Nickname == "" {
+		return identity.Profile{}, fmt.Errorf("%w: %s nickname", ErrMissingScope, p.name)
+	}
+	return profile, nil
+}
+
+// 토큰 엔드포인트 호출
+func (p *provider) requestToken(data url.Values) (*Token, error) {
+	req, err := http.NewRequest("POST", p.config.Endpoint.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string          `json:"access_token"`
+		RefreshToken     string          `json:"refresh_token"`
+		TokenType        string          `json:"token_type"`
+		ExpiresIn        json.RawMessage `json:"expires_in"`
+		Error            string          `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%s 토큰 응답 파싱 실패 (%d): %w", p.name, resp.StatusCode, err)
+	}
+	if body.Error != "" {
+		return nil, &Error{Code: body.Error, Description: body.ErrorDescription}
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("%s 토큰 발급 실패: %d", p.name, resp.StatusCode)
+	}
+
+	return &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		TokenType:    body.TokenType,
+		ExpiresIn:    parseExpiresIn(body.ExpiresIn),
+	}, nil
+}
+
+// expires_in 은 숫자 또는 문자열 (네이버는 "3600" 처럼 문자열로 내려줌)
+func parseExpiresIn(raw json.RawMessage) time.Duration {
+	value := strings.Trim(string(raw), `"`)
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// PKCE S256 code_challenge
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func withQuery(base string, query url.Values) string {
+	if strings.Contains(base, "?") {
+		return base + "&" + query.Encode()
+	}
+	return base + "?" + query.Encode()
+}
@@ -0,0 +1,352 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"guny-world-backend/api/identity"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID     = "client-id"
+	testClientSecret = "client-secret"
+	testRedirectURL  = "https://api.example.com/api/oauth/callback"
+	testAccessToken  = "access-token-1"
+)
+
+// 로컬 가짜 OAuth 서버
+// /authorize 로 받은 code_challenge 를 기억했다가 /token 에서 code_verifier 와 비교
+type fakeServer struct {
+	*httptest.Server
+	t          *testing.T
+	mu         sync.Mutex
+	challenges map[string]string // code → code_challenge
+	userInfo   string
+	userStatus int
+	revoked    []string
+}
+
+func newFakeServer(t *testing.T, userInfo string) *fakeServer {
+	s := &fakeServer{t: t, challenges: map[string]string{}, userInfo: userInfo, userStatus: http.StatusOK}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/userinfo", s.user)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) config() Config {
+	return Config{
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Endpoint: Endpoint{
+			AuthURL:     s.URL + "/authorize",
+			TokenURL:    s.URL + "/token",
+			UserInfoURL: s.URL + "/userinfo",
+		},
+	}
+}
+
+// 사용자가 동의한 것처럼 인가 URL 에 대한 code 발급
+func (s *fakeServer) authorize(authURL string) string {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL || query.Get("response_type") != "code" {
+		s.t.Fatalf("unexpected authorize query %v", query)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	code := fmt.Sprintf("code-%d", len(s.challenges)+1)
+	s.challenges[code] = query.Get("code_challenge")
+	return code
+}
+
+func (s *fakeServer) token(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := r.ParseForm(); err != nil || r.Method != "POST" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	fail := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": code, "error_description": code + " from fake server"})
+	}
+	if r.Form.Get("client_id") != testClientID || r.Form.Get("client_secret") != testClientSecret {
+		fail("invalid_client")
+		return
+	}
+
+	switch r.Form.Get("grant_type") {
+	case "authorization_code":
+		s.mu.Lock()
+		challenge, ok := s.challenges[r.Form.Get("code")]
+		delete(s.challenges, r.Form.Get("code"))
+		s.mu.Unlock()
+		if !ok || r.Form.Get("redirect_uri") != testRedirectURL {
+			fail("invalid_grant")
+			return
+		}
+		if challenge != "" && codeChallenge(r.Form.Get("code_verifier")) != challenge {
+			fail("invalid_grant")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  testAccessToken,
+			"refresh_token": "refresh-token-1",
+			"token_type":    "bearer",
+			"expires_in":    "3600",
+		})
+	case "refresh_token":
+		if r.Form.Get("refresh_token") != "refresh-token-1" {
+			fail("invalid_grant")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-token-2", "token_type": "bearer", "expires_in": 7200})
+	case "delete":
+		s.mu.Lock()
+		s.revoked = append(s.revoked, r.Form.Get("access_token"))
+		s.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"access_token": r.Form.Get("access_token"), "result": "success"})
+	default:
+		fail("unsupported_grant_type")
+	}
+}
+
+func (s *fakeServer) user(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(s.userStatus)
+	fmt.Fprint(w, s.userInfo)
+}
+
+var providerTests = []struct {
+	name       string
+	userInfo   string
+	want       identity.Profile
+	noNickname string
+}{
+	{
+		name:     Naver,
+		userInfo: `{"resultcode":"00","message":"success","response":{"id":"naver-123","nickname":"거니","email":"user@naver.com","name":"홍길동","profile_image":"https://phinf.example/a.png"}}`,
+		want: identity.Profile{
+			Subject: "naver-123", LegacySubject: "email:user@naver.com", Email: "user@naver.com",
+			Nickname: "거니", Name: "홍길동", ProfileImage: "https://phinf.example/a.png",
+		},
+		noNickname: `{"resultcode":"00","message":"success","response":{"id":"naver-123","email":"user@naver.com"}}`,
+	},
+	{
+		name:     Kakao,
+		userInfo: `{"id":4242,"kakao_account":{"email":"user@kakao.com","is_email_verified":true,"profile":{"nickname":"카카오","profile_image_url":"https://k.example/p.jpg"}}}`,
+		want: identity.Profile{
+			Subject: "4242", Email: "user@kakao.com", Nickname: "카카오", ProfileImage: "https://k.example/p.jpg",
+		},
+		noNickname: `{"id":4242,"kakao_account":{"email":"user@kakao.com","is_email_verified":true}}`,
+	},
+	{
+		name:     Google,
+		userInfo: `{"sub":"g-1","email":"user@gmail.com","email_verified":true,"name":"Gildong Hong","picture":"https://g.example/p.png"}`,
+		want: identity.Profile{
+			Subject: "g-1", Email: "user@gmail.com", Nickname: "Gildong Hong", Name: "Gildong Hong", ProfileImage: "https://g.example/p.png",
+		},
+		noNickname: `{"sub":"g-1","email":"user@gmail.com","email_verified":true}`,
+	},
+	{
+		name:     Discord,
+		userInfo: `{"id":"d-1","username":"gildong","global_name":"Gildong","email":"user@example.com","verified":true,"avatar":"abc"}`,
+		want: identity.Profile{
+			Subject: "d-1", Email: "user@example.com", Nickname: "Gildong", Name: "gildong",
+			ProfileImage: "https://cdn.discordapp.com/avatars/d-1/abc.png",
+		},
+		noNickname: `{"id":"d-1","username":"","email":"user@example.com","verified":true}`,
+	},
+}
+
+func TestProviderLogin(t *testing.T) {
+	for _, test := range providerTests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.userInfo)
+			p, err := New(test.name, server.config(), server.Client())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verifier := "verifier-0123456789-0123456789-0123456789-0123"
+			authURL := p.AuthCodeURL("state-1", verifier, false)
+			parsed, _ := url.Parse(authURL)
+			if parsed.Query().Get("state") != "state-1" {
+				t.Fatalf("state missing from %s", authURL)
+			}
+			pkce := definitions[test.name].pkce
+			if got := parsed.Query().Get("code_challenge"); pkce != (got != "") {
+				t.Fatalf("code_challenge = %q, pkce = %v", got, pkce)
+			}
+
+			code := server.authorize(authURL)
+			token, err := p.Exchange(code, "state-1", verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.AccessToken != testAccessToken || token.RefreshToken != "refresh-token-1" || token.ExpiresIn != time.Hour {
+				t.Fatalf("unexpected token %+v", token)
+			}
+
+			profile, err := p.Profile(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if profile != test.want {
+				t.Fatalf("profile = %+v\nwant      %+v", profile, test.want)
+			}
+		})
+	}
+}
+
+func TestProviderPKCE(t *testing.T) {
+	for _, test := range providerTests {
+		if !definitions[test.name].pkce {
+			continue
+		}
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.userInfo)
+			p, _ := New(test.name, server.config(), server.Client())
+
+			code := server.authorize(p.AuthCodeURL("state-1", "the-right-verifier", false))
+			_, err := p.Exchange(code, "state-1", "a-different-verifier")
+			var providerErr *Error
+			if !errors.As(err, &providerErr) || providerErr.Code != "invalid_grant" {
+				t.Fatalf("exchange with wrong verifier: got %v, want invalid_grant", err)
+			}
+		})
+	}
+}
+
+func TestProviderMissingScope(t *testing.T) {
+	for _, test := range providerTests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.noNickname)
+			p, _ := New(test.name, server.config(), server.Client())
+
+			_, err := p.Profile(&Token{AccessToken: testAccessToken})
+			if !errors.Is(err, ErrMissingScope) {
+				t.Fatalf("got %v, want ErrMissingScope", err)
+			}
+		})
+	}
+}
+
+func TestProviderReprompt(t *testing.T) {
+	for _, test := range providerTests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.userInfo)
+			p, _ := New(test.name, server.config(), server.Client())
+
+			normal, _ := url.Parse(p.AuthCodeURL("state-1", "", false))
+			reprompt, _ := url.Parse(p.AuthCodeURL("state-1", "", true))
+			for key, values := range definitions[test.name].reprompt {
+				if normal.Query().Has(key) {
+					t.Fatalf("%s set without reprompt", key)
+				}
+				if reprompt.Query().Get(key) != values[0] {
+					t.Fatalf("%s = %q with reprompt, want %q", key, reprompt.Query().Get(key), values[0])
+				}
+			}
+		})
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	for _, test := range providerTests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newFakeServer(t, test.userInfo)
+
+			// 코드 재사용
+			p, _ := New(test.name, server.config(), server.Client())
+			code := server.authorize(p.AuthCodeURL("state-1", "verifier", false))
+			if _, err := p.Exchange(code, "state-1", "verifier"); err != nil {
+				t.Fatal(err)
+			}
+			var providerErr *Error
+			if _, err := p.Exchange(code, "state-1", "verifier"); !errors.As(err, &providerErr) || providerErr.Code != "invalid_grant" {
+				t.Fatalf("reused code: got %v, want invalid_grant", err)
+			}
+
+			// 잘못된 클라이언트 시크릿
+			config := server.config()
+			config.ClientSecret = "wrong"
+			wrong, _ := New(test.name, config, server.Client())
+			code = server.authorize(wrong.AuthCodeURL("state-1", "verifier", false))
+			if _, err := wrong.Exchange(code, "state-1", "verifier"); !errors.As(err, &providerErr) || providerErr.Code != "invalid_client" {
+				t.Fatalf("wrong secret: got %v, want invalid_client", err)
+			}
+
+			// 사용자 정보 조회 실패
+			if _, err := p.Profile(&Token{AccessToken: "expired"}); err == nil {
+				t.Fatal("profile with rejected access token succeeded")
+			}
+			server.userStatus = http.StatusInternalServerError
+			if _, err := p.Profile(&Token{AccessToken: testAccessToken}); err == nil {
+				t.Fatal("profile with server error succeeded")
+			}
+		})
+	}
+}
+
+func TestNaverErrorResult(t *testing.T) {
+	server := newFakeServer(t, `{"resultcode":"024","message":"Authentication failed"}`)
+	p, _ := New(Naver, server.config(), server.Client())
+	if _, err := p.Profile(&Token{AccessToken: testAccessToken}); err == nil || errors.Is(err, ErrMissingScope) {
+		t.Fatalf("got %v, want naver error", err)
+	}
+}
+
+func TestProviderRefresh(t *testing.T) {
+	server := newFakeServer(t, providerTests[0].userInfo)
+	p, _ := New(Naver, server.config(), server.Client())
+
+	token, err := p.Refresh("refresh-token-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 새 리프레시 토큰을 주지 않으면 기존 값 유지
+	if token.AccessToken != "access-token-2" || token.RefreshToken != "refresh-token-1" || token.ExpiresIn != 2*time.Hour {
+		t.Fatalf("unexpected token %+v", token)
+	}
+
+	var providerErr *Error
+	if _, err := p.Refresh("unknown"); !errors.As(err, &providerErr) || providerErr.Code != "invalid_grant" {
+		t.Fatalf("got %v, want invalid_grant", err)
+	}
+}
+
+func TestNaverRevoke(t *testing.T) {
+	server := newFakeServer(t, providerTests[0].userInfo)
+	p, _ := New(Naver, server.config(), server.Client())
+
+	if err := p.Revoke(&Token{AccessToken: testAccessToken}); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.revoked) != 1 || server.revoked[0] != testAccessToken {
+		t.Fatalf("revoked = %v", server.revoked)
+	}
+
+	// 폐기를 지원하지 않는 제공자는 요청하지 않음
+	kakao, _ := New(Kakao, server.config(), server.Client())
+	if err := kakao.Revoke(&Token{AccessToken: testAccessToken}); err != nil || len(server.revoked) != 1 {
+		t.Fatalf("kakao revoke: err=%v revoked=%v", err, server.revoked)
+	}
+}
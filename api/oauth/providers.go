@@ -0,0 +1,294 @@
+// oauth/providers.go
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"guny-world-backend/api/identity"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 지원하는 제공자
+const (
+	Naver   = identity.ProviderNaver
+	Kakao   = "kakao"
+	Google  = "google"
+	Discord = "discord"
+)
+
+// 제공자별 기본 설정과 사용자 정보 변환
+var definitions = map[string]struct {
+	config     Config
+	pkce       bool
+	reprompt   url.Values
+	mapProfile func(body []byte) (identity.Profile, error)
+	revoke     func(p *provider, token *Token) error
+}{
+	Naver: {
+		reprompt: url.Values{"auth_type": {"reprompt"}},
+		config: Config{
+			Endpoint: Endpoint{
+				AuthURL:     "https://nid.naver.com/oauth2.0/authorize",
+				TokenURL:    "https://nid.naver.com/oauth2.0/token",
+				UserInfoURL: "https://openapi.naver.com/v1/nid/me",
+			},
+		},
+		mapProfile: naverProfile,
+		revoke:     naverRevoke,
+	},
+	Kakao: {
+		pkce: true,
+		config: Config{
+			Scopes: []string{"profile_nickname", "profile_image", "account_email"},
+			Endpoint: Endpoint{
+				AuthURL:     "https://kauth.kakao.com/oauth/authorize",
+				TokenURL:    "https://kauth.kakao.com/oauth/token",
+				UserInfoURL: "https://kapi.kakao.com/v2/user/me",
+			},
+		},
+		mapProfile: kakaoProfile,
+	},
+	Google: {
+		pkce:     true,
+		reprompt: url.Values{"prompt": {"consent"}},
+		config: Config{
+			Scopes: []string{"openid", "email", "profile"},
+			Endpoint: Endpoint{
+				AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+				TokenURL:    "https://oauth2.googleapis.com/token",
+				UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+			},
+		},
+		mapProfile: googleProfile,
+	},
+	Discord: {
+		reprompt: url.Values{"prompt": {"consent"}},
+		config: Config{
+			Scopes: []string{"identify", "email"},
+			Endpoint: Endpoint{
+				AuthURL:     "https://discord.com/oauth2/authorize",
+				TokenURL:    "https://discord.com/api/oauth2/token",
+				UserInfoURL: "https://discord.com/api/users/@me",
+			},
+		},
+		mapProfile: discordProfile,
+	},
+}
+
+var client = &http.Client{Timeout: time.Second * 10}
+
+// 환경변수 설정으로 제공자 생성
+//
+//	<NAME>_CLIENT_ID       클라이언트 ID (없으면 사용 불가)
+//	<NAME>_CLIENT_SECRET   클라이언트 시크릿
+//	<NAME>_REDIRECT_URL    콜백 주소
+//	<NAME>_SCOPES          공백으로 구분한 scope (기본값은 제공자별)
+//	<NAME>_AUTH_URL, <NAME>_TOKEN_URL, <NAME>_USERINFO_URL
+//	                       엔드포인트 변경 (로컬 가짜 OAuth 서버로 테스트할 때)
+func Get(name string) (Provider, error) {
+	definition, ok := definitions[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	prefix := strings.ToUpper(name) + "_"
+	config := definition.config
+	config.ClientID = os.Getenv(prefix + "CLIENT_ID")
+	config.ClientSecret = os.Getenv(prefix + "CLIENT_SECRET")
+	config.RedirectURL = envOr(prefix+"REDIRECT_URL", config.RedirectURL)
+	if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+		config.Scopes = strings.Fields(scopes)
+	}
+	config.Endpoint.AuthURL = envOr(prefix+"AUTH_URL", config.Endpoint.AuthURL)
+	config.Endpoint.TokenURL = envOr(prefix+"TOKEN_URL", config.Endpoint.TokenURL)
+	config.Endpoint.UserInfoURL = envOr(prefix+"USERINFO_URL", config.Endpoint.UserInfoURL)
+
+	if config.ClientID == "" || config.RedirectURL == "" {
+		return nil, ErrNotConfigured
+	}
+	return New(name, config, client)
+}
+
+// 설정을 직접 지정해 제공자 생성 (테스트에서 가짜 서버 주소와 클라이언트 사용)
+func New(name string, config Config, httpClient *http.Client) (Provider, error) {
+	definition, ok := definitions[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	if httpClient == nil {
+		httpClient = client
+	}
+	return &provider{name: name, config: config, client: httpClient, pkce: definition.pkce, reprompt: definition.reprompt, mapProfile: definition.mapProfile, revoke: definition.revoke}, nil
+}
+
+// 네이버 회원 프로필 조회 응답
+func naverProfile(body []byte) (identity.Profile, error) {
+	var userInfo struct {
+		ResultCode string `json:"resultcode"`
+		Message    string `json:"message"`
+		Response   struct {
+			Id           string `json:"id"`
+			Nickname     string `json:"nickname"`
+			Email        string `json:"email"`
+			Name         string `json:"name"`
+			ProfileImage string `json:"profile_image"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return identity.Profile{}, err
+	}
+	if userInfo.ResultCode != "" && userInfo.ResultCode != "00" {
+		return identity.Profile{}, errors.New("naver: " + userInfo.Message)
+	}
+
+	// 이메일은 사용자가 바꾸거나 제공에 동의하지 않을 수 있으므로 식별자는 id 를 사용
+	// 이전에는 이메일을 식별자로 저장했음 (migrations/012 에서 "email:" 을 붙여 구분)
+	profile := identity.Profile{
+		Subject:      userInfo.Response.Id,
+		Email:        userInfo.Response.Email,
+		Nickname:     userInfo.Response.Nickname,
+		Name:         userInfo.Response.Name,
+		ProfileImage: userInfo.Response.ProfileImage,
+	}
+	if profile.Email != "" {
+		profile.LegacySubject = "email:" + profile.Email
+	}
+	return profile, nil
+}
+
+// 네이버 연동 해제 (grant_type=delete, 유효한 엑세스 토큰 필요)
+func naverRevoke(p *provider, token *Token) error {
+	data := url.Values{}
+	data.Set("grant_type", "delete")
+	data.Set("client_id", p.config.ClientID)
+	data.Set("client_secret", p.config.ClientSecret)
+	data.Set("access_token", token.AccessToken)
+	data.Set("service_provider", "NAVER")
+
+	req, err := http.NewRequest("POST", p.config.Endpoint.TokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Result           string `json:"result"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+	if body.Error != "" {
+		return &Error{Code: body.Error, Description: body.ErrorDescription}
+	}
+	if body.Result != "success" {
+		return errors.New("naver: 연동 해제 실패 " + body.Result)
+	}
+	return nil
+}
+
+// 카카오 사용자 정보 조회 응답
+func kakaoProfile(body []byte) (identity.Profile, error) {
+	var userInfo struct {
+		Id           int64 `json:"id"`
+		KakaoAccount struct {
+			Email           string `json:"email"`
+			IsEmailVerified bool   `json:"is_email_verified"`
+			Profile         struct {
+				Nickname        string `json:"nickname"`
+				ProfileImageURL string `json:"profile_image_url"`
+			} `json:"profile"`
+		} `json:"kakao_account"`
+	}
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return identity.Profile{}, err
+	}
+	if userInfo.Id == 0 {
+		return identity.Profile{}, nil
+	}
+
+	profile := identity.Profile{
+		Subject:      strconv.FormatInt(userInfo.Id, 10),
+		Nickname:     userInfo.KakaoAccount.Profile.Nickname,
+		ProfileImage: userInfo.KakaoAccount.Profile.ProfileImageURL,
+	}
+	if userInfo.KakaoAccount.IsEmailVerified {
+		profile.Email = userInfo.KakaoAccount.Email
+	}
+	return profile, nil
+}
+
+// 구글 OpenID Connect userinfo 응답
+func googleProfile(body []byte) (identity.Profile, error) {
+	var userInfo struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return identity.Profile{}, err
+	}
+
+	profile := identity.Profile{
+		Subject:      userInfo.Sub,
+		Nickname:     userInfo.Name,
+		Name:         userInfo.Name,
+		ProfileImage: userInfo.Picture,
+	}
+	if userInfo.EmailVerified {
+		profile.Email = userInfo.Email
+	}
+	return profile, nil
+}
+
+// 디스코드 현재 사용자 조회 응답
+func discordProfile(body []byte) (identity.Profile, error) {
+	var userInfo struct {
+		Id         string `json:"id"`
+		Username   string `json:"username"`
+		GlobalName string `json:"global_name"`
+		Email      string `json:"email"`
+		Verified   bool   `json:"verified"`
+		Avatar     string `json:"avatar"`
+	}
+	if err := json.Unmarshal(body, &userInfo); err != nil {
+		return identity.Profile{}, err
+	}
+
+	profile := identity.Profile{
+		Subject:  userInfo.Id,
+		Nickname: userInfo.GlobalName,
+		Name:     userInfo.Username,
+	}
+	if profile.Nickname == "" {
+		profile.Nickname = userInfo.Username
+	}
+	if userInfo.Avatar != "" {
+		profile.ProfileImage = "https://cdn.discordapp.com/avatars/" + userInfo.Id + "/" + userInfo.Avatar + ".png"
+	}
+	if userInfo.Verified {
+		profile.Email = userInfo.Email
+	}
+	return profile, nil
+}
+
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
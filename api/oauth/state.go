@@ -0,0 +1,107 @@
+// oauth/state.go
+package oauth
+
+import (
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"time"
+)
+
+// state 유효 기간 (제공자 로그인 화면에서 머무는 시간)
+const stateTTL = time.Minute * 10
+
+var (
+	ErrStateMissing = errors.New("로그인 요청 정보가 없습니다. 다시 시도해 주세요.")
+	ErrStateInvalid = errors.New("올바르지 않은 로그인 요청입니다. 다시 시도해 주세요.")
+	ErrStateUsed    = errors.New("이미 처리된 로그인 요청입니다. 다시 시도해 주세요.")
+	ErrStateExpired = errors.New("로그인 요청이 만료되었습니다. 다시 시도해 주세요.")
+)
+
+// 로그인 시작 시 저장해 둔 정보
+type State struct {
+	Provider     string
+	AccountId    string // 계정 연결이면 연결할 계정 ID, 로그인이면 빈 값
+	CodeVerifier string
+	NonceHash    string // 로그인을 시작한 프론트엔드의 nonce 해시 (교환 코드에 그대로 옮김)
+}
+
+// state 와 PKCE code_verifier 를 새로 만들어 저장
+// binding 은 브라우저 쿠키에 넣어 둔 값으로, 콜백에서 같은 브라우저인지 확인하는 데 사용
+// nonce 는 로그인을 시작한 프론트엔드가 보관하는 값으로, 1회용 코드 교환 때 다시 확인 (계정 연결이면 빈 값)
+func NewState(provider, accountId, binding, nonce string) (state string, verifier string, err error) {
+	state, stateHash, err := onetime.New()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, _, err = onetime.New()
+	if err != nil {
+		return "", "", err
+	}
+
+	var owner, nonceHash sql.NullString
+	if accountId != "" {
+		owner = sql.NullString{String: accountId, Valid: true}
+	}
+	if nonce != "" {
+		nonceHash = sql.NullString{String: onetime.Hash(nonce), Valid: true}
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("INSERT INTO oauth_states (state_hash, provider, binding_hash, account_id, code_verifier, nonce_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		stateHash, provider, onetime.Hash(binding), owner, verifier, nonceHash, now.Add(stateTTL), now)
+	if err != nil {
+		return "", "", err
+	}
+	return state, verifier, nil
+}
+
+// 콜백으로 돌아온 state 를 확인하고 1회 사용 처리
+func ConsumeState(provider, state, binding string) (*State, error) {
+	if state == "" || binding == "" {
+		return nil, ErrStateMissing
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stateHash := onetime.Hash(state)
+	var row struct {
+		Provider     string         `db:"provider"`
+		BindingHash  string         `db:"binding_hash"`
+		AccountId    sql.NullString `db:"account_id"`
+		CodeVerifier string         `db:"code_verifier"`
+		NonceHash    sql.NullString `db:"nonce_hash"`
+		ExpiresAt    time.Time      `db:"expires_at"`
+		UsedAt       sql.NullTime   `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT provider, binding_hash, account_id, code_verifier, nonce_hash, expires_at, used_at FROM oauth_states WHERE state_hash = ? FOR UPDATE", stateHash)
+	if err == sql.ErrNoRows {
+		return nil, ErrStateInvalid
+	} else if err != nil {
+		return nil, err
+	}
+
+	// 다른 브라우저에서 시작했거나 다른 제공자의 state 면 거부
+	if row.Provider != provider || row.BindingHash != onetime.Hash(binding) {
+		return nil, ErrStateInvalid
+	}
+	if row.UsedAt.Valid {
+		return nil, ErrStateUsed
+	}
+	if time.Now().After(row.ExpiresAt) {
+		return nil, ErrStateExpired
+	}
+
+	if _, err = tx.Exec("UPDATE oauth_states SET used_at = ? WHERE state_hash = ?", time.Now(), stateHash); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &State{Provider: row.Provider, AccountId: row.AccountId.String, CodeVerifier: row.CodeVerifier, NonceHash: row.NonceHash.String}, nil
+}
@@ -0,0 +1,80 @@
+// oauth/tokens.go
+package oauth
+
+import (
+	"errors"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/identity"
+	"log"
+	"time"
+)
+
+// 만료 직전 토큰은 미리 갱신
+const refreshLeeway = time.Minute
+
+var ErrNoToken = errors.New("저장된 외부 로그인 토큰이 없습니다")
+
+// 제공자에게 받은 토큰을 연결된 외부 계정에 저장
+func StoreTokens(providerName, subject string, token *Token) error {
+	tokens := identity.Tokens{AccessToken: token.AccessToken, RefreshToken: token.RefreshToken}
+	if token.ExpiresIn > 0 {
+		tokens.ExpiresAt = time.Now().Add(token.ExpiresIn)
+	}
+	return identity.SaveTokens(providerName, subject, tokens)
+}
+
+// 저장돼 있던 토큰을 제공자에 폐기 요청 (연결 해제, 탈퇴 시)
+// 연결 정보를 지우는 경로에서만 쓰므로 폐기하려고 갱신한 토큰은 저장하지 않음
+func RevokeTokens(providerName string, tokens identity.Tokens) error {
+	provider, err := Get(providerName)
+	if err != nil {
+		return err
+	}
+	token, err := usable(provider, tokens)
+	if err != nil {
+		return err
+	}
+	return provider.Revoke(token)
+}
+
+// 계정에 연결된 모든 외부 로그인의 저장된 토큰 (제공자별, 연결 정보를 지우기 전에 조회)
+func LoadAccountTokens(accountId string) (map[string]identity.Tokens, error) {
+	var providers []string
+	err := database.DB.Select(&providers, "SELECT provider FROM identities WHERE account_id = ?", accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]identity.Tokens, len(providers))
+	for _, providerName := range providers {
+		// 읽을 수 없는 토큰은 폐기하지 못해도 연결 정보 삭제는 계속 진행
+		_, stored, err := identity.LoadTokens(accountId, providerName)
+		if err != nil {
+			log.Println("외부 로그인 토큰 조회 실패: ", providerName, err)
+			continue
+		}
+		tokens[providerName] = stored
+	}
+	return tokens, nil
+}
+
+// 조회해 둔 토큰을 모두 제공자에 폐기 요청 (실패해도 나머지는 계속 진행)
+func RevokeAll(tokens map[string]identity.Tokens) {
+	for providerName, stored := range tokens {
+		if err := RevokeTokens(providerName, stored); err != nil && err != ErrNoToken {
+			log.Println("외부 로그인 토큰 폐기 실패: ", providerName, err)
+		}
+	}
+}
+
+// 그대로 쓸 수 있는 토큰이면 그대로, 만료됐으면 리프레시 토큰으로 갱신
+func usable(provider Provider, tokens identity.Tokens) (*Token, error) {
+	expired := !tokens.ExpiresAt.IsZero() && time.Now().Add(refreshLeeway).After(tokens.ExpiresAt)
+	if tokens.AccessToken != "" && !expired {
+		return &Token{AccessToken: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+	}
+	if tokens.RefreshToken == "" {
+		return nil, ErrNoToken
+	}
+	return provider.Refresh(tokens.RefreshToken)
+}
@@ -0,0 +1,25 @@
+// onetime/onetime.go
+package onetime
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// 메일 링크 등에 쓰는 1회용 무작위 토큰과 저장용 해시 생성
+func New() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, Hash(token), nil
+}
+
+// 토큰 원문 대신 DB 에 저장하는 SHA-256 해시
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+// password/change.go
+package password
+
+import (
+	"database/sql"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/register"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/throttle"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 로그인한 사용자의 비밀번호 확인 실패 추적 키 (비밀번호 변경, 회원 탈퇴에서 같은 기록 사용)
+func AttemptKey(userId string) string {
+	return "password:" + userId
+}
+
+// 비밀번호 변경 핸들러 (auth.RequireAuth 뒤에서 동작)
+func Change(c *fiber.Ctx) (err error) {
+	db := database.DB
+	principal := auth.GetPrincipal(c)
+
+	type RequestQuery struct {
+		CurrentPassword     string `json:"currentPassword"`
+		NewPassword         string `json:"newPassword"`
+		LogoutOtherSessions bool   `json:"logoutOtherSessions"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+	if requestQuery.CurrentPassword == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "현재 비밀번호 값이 존재하지 않습니다."})
+	}
+
+	// 현재 비밀번호 가져오기 (네이버 로그인 계정은 비밀번호가 없음)
+	var current string
+	err = db.Get(&current, "SELECT password FROM accounts WHERE id = ? AND password IS NOT NULL", principal.UserId)
+	if err == sql.ErrNoRows {
+		return c.Status(400).JSON(fiber.Map{"error": "비밀번호로 가입한 계정만 비밀번호를 변경할 수 있습니다."})
+	} else if err != nil {
+		log.Println("데이터베이스 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 탈취한 엑세스 토큰으로 비밀번호를 대입하지 못하도록 로그인과 같은 실패 추적기 사용
+	attempt, retryAfter, err := throttle.Default().Begin(AttemptKey(principal.UserId), c.IP())
+	if err != nil {
+		log.Println("비밀번호 실패 기록 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if retryAfter > 0 {
+		return throttle.Reject(c, retryAfter)
+	}
+
+	// 현재 비밀번호 검증
+	if err := bcrypt.CompareHashAndPassword([]byte(current), []byte(requestQuery.CurrentPassword)); err != nil {
+		attempt.Fail()
+		return c.Status(400).JSON(fiber.Map{"error": "현재 비밀번호가 일치하지 않습니다."})
+	}
+	if err := attempt.Succeed(); err != nil {
+		log.Println("비밀번호 실패 기록 초기화 에러: ", err)
+	}
+
+	// 비밀번호 정책 확인
+	if err := register.ValidatePassword(requestQuery.NewPassword); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	hashedPassword, err := register.HashPassword(requestQuery.NewPassword)
+	if err != nil {
+		log.Println("비밀번호 해시 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "비밀번호를 처리하는 데 실패했습니다."})
+	}
+
+	if _, err = db.Exec("UPDATE accounts SET password = ?, updated_at = ? WHERE id = ?", hashedPassword, time.Now(), principal.UserId); err != nil {
+		log.Println("비밀번호 변경 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 요청한 경우 지금 기기를 제외한 다른 기기 로그아웃
+	if requestQuery.LogoutOtherSessions {
+		if err := session.RevokeOthers(principal.UserId, principal.SessionId); err != nil {
+			log.Println("다른 세션 폐기 실패: ", err)
+			return c.Status(500).JSON(fiber.Map{"error": "비밀번호는 변경되었지만 다른 기기를 로그아웃하지 못했습니다."})
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "비밀번호가 변경되었습니다."})
+}
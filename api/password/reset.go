@@ -0,0 +1,167 @@
+// password/reset.go
+package password
+
+import (
+	"database/sql"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/mail"
+	"guny-world-backend/api/onetime"
+	"guny-world-backend/api/register"
+	"guny-world-backend/api/session"
+	"log"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// 재설정 링크 유효 기간
+	resetTokenTTL = time.Minute * 30
+	// 같은 계정으로 재설정 메일을 다시 보내기까지의 최소 간격
+	resetInterval = time.Minute
+)
+
+// 계정 존재 여부와 관계없이 같은 응답
+const resetRequestedMessage = "가입된 이메일이라면 비밀번호 재설정 메일이 발송됩니다."
+
+// 비밀번호 재설정 메일 요청 핸들러
+func RequestReset(c *fiber.Ctx) (err error) {
+	db := database.DB
+
+	type RequestQuery struct {
+		UserId string `json:"user_id"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.UserId == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "아이디 값이 존재하지 않습니다."})
+	}
+
+	var id string
+	err = db.Get(&id, "SELECT id FROM accounts WHERE email = ?", requestQuery.UserId)
+	if err == sql.ErrNoRows {
+		return c.Status(200).JSON(fiber.Map{"message": resetRequestedMessage})
+	} else if err != nil {
+		log.Println("사용자 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 짧은 시간 안에 반복 요청하면 조용히 무시
+	var recent int
+	err = db.Get(&recent, "SELECT COUNT(*) FROM password_resets WHERE user_id = ? AND created_at > ?", id, time.Now().Add(-resetInterval))
+	if err != nil {
+		log.Println("재설정 이력 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if recent > 0 {
+		return c.Status(200).JSON(fiber.Map{"message": resetRequestedMessage})
+	}
+
+	// 메일 발송 시간으로 계정 존재 여부가 드러나지 않도록 비동기 발송
+	email := requestQuery.UserId
+	go func() {
+		if err := SendResetLink(id, email); err != nil {
+			log.Println("재설정 메일 발송 실패: ", err)
+		}
+	}()
+
+	return c.Status(200).JSON(fiber.Map{"message": resetRequestedMessage})
+}
+
+// 재설정 토큰을 저장하고 재설정 링크 메일 발송 (관리자의 강제 재설정에서도 사용)
+func SendResetLink(userId, email string) error {
+	token, hash, err := onetime.New()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("INSERT INTO password_resets (token_hash, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)",
+		hash, userId, now.Add(resetTokenTTL), now)
+	if err != nil {
+		return err
+	}
+
+	link := os.Getenv("FRONTEND_URL") + "/reset-password?token=" + url.QueryEscape(token)
+	return mail.Default().Send(mail.Message{
+		To:      email,
+		Subject: "[거니월드] 비밀번호 재설정 안내",
+		Body:    "아래 링크에서 새 비밀번호를 설정해 주세요. 링크는 30분 동안 한 번만 사용할 수 있습니다.\n\n" + link + "\n\n본인이 요청하지 않았다면 이 메일을 무시해 주세요.",
+	})
+}
+
+// 비밀번호 재설정 확인 핸들러
+func ConfirmReset(c *fiber.Ctx) (err error) {
+	type RequestQuery struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.Token == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "재설정 토큰 값이 존재하지 않습니다."})
+	}
+
+	// 비밀번호 정책 확인
+	if err := register.ValidatePassword(requestQuery.Password); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	hashedPassword, err := register.HashPassword(requestQuery.Password)
+	if err != nil {
+		log.Println("비밀번호 해시 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "비밀번호를 처리하는 데 실패했습니다."})
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		log.Println("트랜잭션 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	defer tx.Rollback()
+
+	var row struct {
+		UserId    string       `db:"user_id"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT user_id, expires_at, used_at FROM password_resets WHERE token_hash = ? FOR UPDATE", onetime.Hash(requestQuery.Token))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("재설정 토큰 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if err == sql.ErrNoRows || row.UsedAt.Valid || time.Now().After(row.ExpiresAt) {
+		return c.Status(400).JSON(fiber.Map{"error": "만료되었거나 유효하지 않은 재설정 링크입니다."})
+	}
+
+	// 메일로 받은 링크를 사용했으므로 이메일 인증도 함께 처리
+	now := time.Now()
+	_, err = tx.Exec("UPDATE accounts SET password = ?, email_verified_at = COALESCE(email_verified_at, ?), updated_at = ? WHERE id = ?", hashedPassword, now, now, row.UserId)
+	if err != nil {
+		log.Println("비밀번호 변경 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 같은 사용자의 남은 재설정 링크도 모두 사용 처리
+	if _, err = tx.Exec("UPDATE password_resets SET used_at = ? WHERE user_id = ? AND used_at IS NULL", now, row.UserId); err != nil {
+		log.Println("재설정 토큰 사용 처리 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println("트랜잭션 커밋 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 기존 로그인 세션 모두 폐기
+	if err := session.RevokeAllForUser(row.UserId); err != nil {
+		log.Println("세션 폐기 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "비밀번호는 변경되었지만 기존 로그인을 해제하지 못했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "비밀번호가 재설정되었습니다. 다시 로그인해 주세요."})
+}
@@ -2,9 +2,13 @@
 package register
 
 import (
+	"errors"
 	"guny-world-backend/api/database"
+	"guny-world-backend/api/verification"
 	"log"
 	"regexp"
+	"strconv"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gofiber/fiber/v2"
@@ -39,7 +43,7 @@ func Register(c *fiber.Ctx) (err error) {
 
     // 사용자 이름 중복 확인
     var count int
-    err = db.Get(&count, "SELECT COUNT(*) FROM users WHERE user_id = ?", requestQuery.UserId)
+    err = db.Get(&count, "SELECT COUNT(*) FROM accounts WHERE email = ?", requestQuery.UserId)
     if err != nil {
         log.Println("Error : 서버 내부 오류")
         return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
@@ -56,35 +60,64 @@ func Register(c *fiber.Ctx) (err error) {
         return c.Status(400).JSON(fiber.Map{"error": "아이디는 올바른 이메일 형식이어야 합니다."})
     }
 
-    // 비밀번호가 8자리 이상인지 확인
-    if len(requestQuery.Password) < 8 {
-        return c.Status(400).JSON(fiber.Map{"error": "비밀번호는 최소 8자 이상이어야 합니다."})
+    // 비밀번호 정책 확인
+    if err := ValidatePassword(requestQuery.Password); err != nil {
+        return c.Status(400).JSON(fiber.Map{"error": err.Error()})
     }
 
-    // 닉네임 길이 확인 (한국어 기준 6글자, 영어 기준 12글자)
-    if utf8.RuneCountInString(requestQuery.Nickname) > 8 || len(requestQuery.Nickname) > 16 {
-        return c.Status(400).JSON(fiber.Map{"error": "닉네임은 한국어 최대 6글자 또는 영어 최대 12글자 이내여야 합니다."})
+    // 닉네임 길이 확인
+    if err := ValidateNickname(requestQuery.Nickname); err != nil {
+        return c.Status(400).JSON(fiber.Map{"error": err.Error()})
     }
 
     // 비번 해쉬
-    hashedPassword, err := hashPassword(requestQuery.Password)
+    hashedPassword, err := HashPassword(requestQuery.Password)
     if err != nil {
         log.Println("Error : 비밀번호 해시 생성 실패", err)
         return c.Status(500).JSON(fiber.Map{"error": "비밀번호를 처리하는 데 실패했습니다."})
     }
 
     // 사용자 정보 저장
-    _, err = db.Exec("INSERT INTO users (user_id, password, nickname) VALUES (?, ?, ?)", requestQuery.UserId, hashedPassword, requestQuery.Nickname)
+    result, err := db.Exec("INSERT INTO accounts (email, password, nickname, created_at) VALUES (?, ?, ?, ?)", requestQuery.UserId, hashedPassword, requestQuery.Nickname, time.Now())
     if err != nil {
         log.Println("Error : 사용자 정보 저장 실패", err)
         return c.Status(500).JSON(fiber.Map{"error": "사용자 정보를 저장하는 데 실패했습니다."})
     }
 
-    return c.Status(200).JSON(fiber.Map{"message": "회원가입이 성공!"})
+    // 인증 메일 발송 (실패해도 가입은 유지하고 재발송으로 처리)
+    id, err := result.LastInsertId()
+    if err == nil {
+        err = verification.Send(strconv.FormatInt(id, 10), requestQuery.UserId)
+    }
+    if err != nil {
+        log.Println("Error : 인증 메일 발송 실패", err)
+    }
+
+    return c.Status(200).JSON(fiber.Map{"message": "회원가입이 성공! 이메일 인증 후 로그인할 수 있습니다."})
+}
+
+// 비밀번호 정책 확인 (회원가입, 비밀번호 재설정/변경에서 공통 사용)
+func ValidatePassword(password string) error {
+    // 비밀번호가 8자리 이상인지 확인
+    if len(password) < 8 {
+        return errors.New("비밀번호는 최소 8자 이상이어야 합니다.")
+    }
+    return nil
+}
+
+// 닉네임 정책 확인 (한국어 기준 6글자, 영어 기준 12글자)
+func ValidateNickname(nickname string) error {
+    if nickname == "" {
+        return errors.New("닉네임 값이 존재하지 않습니다.")
+    }
+    if utf8.RuneCountInString(nickname) > 8 || len(nickname) > 16 {
+        return errors.New("닉네임은 한국어 최대 6글자 또는 영어 최대 12글자 이내여야 합니다.")
+    }
+    return nil
 }
 
 // 해쉬 함수
-func hashPassword(password string) (hashedPassword string, err error) {
+func HashPassword(password string) (hashedPassword string, err error) {
     passwordBytes := []byte(password)
 
     bytePass, err := bcrypt.GenerateFromPassword(passwordBytes, bcrypt.DefaultCost)
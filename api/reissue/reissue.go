@@ -1,14 +1,20 @@
 package reissue
 
 import (
+	"errors"
+	"guny-world-backend/api/role"
+	"guny-world-backend/api/session"
+	"guny-world-backend/api/suspension"
+	"guny-world-backend/api/token"
 	"log"
-	"os"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gofiber/fiber/v2"
 )
 
+// 재발급 중 정지된 계정을 만났을 때 (기존 리프레시 토큰은 사용 처리하지 않음)
+var errSuspended = errors.New("정지된 계정입니다")
+
 func Reissue(c *fiber.Ctx) (err error) {
     type RequestQuery struct {
         RefreshToken string `json:"refreshToken"`
@@ -21,82 +27,61 @@ func Reissue(c *fiber.Ctx) (err error) {
         return c.Status(400).JSON(fiber.Map{"error": "값을 입력해 주세요."})
     }
 
-    // JWT 키 불러오기
-    jwtSecret := os.Getenv("JWT_SECRET_TOKEN")
-    if jwtSecret == "" {
-        log.Println("JWT 시크릿 키가 설정되지 않았습니다.")
-        return c.Status(500).JSON(fiber.Map{"error": "서버 설정 오류입니다. 관리자에게 문의하세요."})
-    }
-
     // 리프레시 토큰 검증 및 파싱
-    token, err := jwt.ParseWithClaims(requestQuery.RefreshToken, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-        return []byte(jwtSecret), nil
-    })
+    tokens := token.Default()
+    claims, err := tokens.Parse(requestQuery.RefreshToken, token.TypeRefresh)
     if err != nil {
         log.Println("리프레시 토큰 검증 실패: ", err)
         return c.Status(401).JSON(fiber.Map{"error": "유효하지 않은 리프레시 토큰입니다."})
     }
 
-    claims, ok := token.Claims.(*Claims)
-    if !ok || !token.Valid {
-        log.Println("토큰 클레임 검증 실패")
+    // 저장된 리프레시 토큰인지 확인하고, 새 토큰 저장까지 한 번에 1회 사용 처리
+    // 중간에 실패하면 기존 토큰이 그대로 남아 같은 토큰으로 다시 시도해도 재사용으로 보지 않음
+    var suspended *suspension.Suspension
+    var accessToken, refreshToken string
+    _, familyId, err := session.Rotate(requestQuery.RefreshToken, func(userId, familyId string) (string, time.Time, error) {
+        // 정지된 계정은 재발급 불가
+        s, err := suspension.Get(userId)
+        if err != nil {
+            return "", time.Time{}, err
+        }
+        if s != nil {
+            suspended = s
+            return "", time.Time{}, errSuspended
+        }
+
+        // 현재 역할로 새로운 엑세스 토큰 생성
+        roles, err := role.List(userId)
+        if err != nil {
+            return "", time.Time{}, err
+        }
+        accessToken, err = tokens.NewAccessToken(userId, familyId, roles)
+        if err != nil {
+            return "", time.Time{}, err
+        }
+
+        // 같은 패밀리로 새로운 리프레시 토큰 생성
+        var expiresAt time.Time
+        refreshToken, expiresAt, err = tokens.NewRefreshToken(userId, familyId)
+        return refreshToken, expiresAt, err
+    })
+    if err == session.ErrReused {
+        log.Println("리프레시 토큰 재사용 감지, 패밀리 폐기: ", claims.UserId)
+        return c.Status(401).JSON(fiber.Map{"error": "이미 사용된 리프레시 토큰입니다. 다시 로그인해 주세요."})
+    } else if err == session.ErrNotFound || err == session.ErrExpired || err == session.ErrRevoked {
+        log.Println("리프레시 토큰 사용 불가: ", err)
         return c.Status(401).JSON(fiber.Map{"error": "유효하지 않은 리프레시 토큰입니다."})
-    }
-
-    // 새로운 엑세스 토큰 생성
-    accessToken, err := makeAccessToken(claims.UserId, jwtSecret)
-    if err != nil {
-        log.Println("엑세스 토큰 생성 실패: ", err)
+    } else if err == errSuspended {
+        return suspension.Reject(c, suspended)
+    } else if err != nil {
+        log.Println("토큰 재발급 실패: ", err)
         return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
     }
 
-    // 새로운 리프레시 토큰 생성
-    refreshToken, err := makeRefreshToken(claims.UserId, jwtSecret)
-    if err != nil {
-        log.Println("리프레시 토큰 생성 실패: ", err)
-        return c.Status(500).JSON(fiber.Map{"error": "토큰 생성에 실패했습니다."})
+    // 세션 마지막 사용 정보 갱신
+    if err := session.Touch(familyId, c.Get("User-Agent"), c.IP()); err != nil {
+        log.Println("세션 갱신 실패: ", err)
     }
 
     return c.Status(200).JSON(fiber.Map{"message": "토큰 재발급 성공!", "accessToken": accessToken, "refreshToken": refreshToken})
 }
-
-type Claims struct {
-    UserId string `json:"user_id"`
-    jwt.StandardClaims
-}
-
-func makeAccessToken(userId string, jwtSecret string) (accessToken string, err error) {
-    claims := Claims{
-        UserId: userId,
-        StandardClaims: jwt.StandardClaims{
-            ExpiresAt: time.Now().Add(time.Hour * 1).Unix(),
-            Issuer:    "flexible-quest",
-        },
-    }
-
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    accessToken, err = token.SignedString([]byte(jwtSecret))
-    if err != nil {
-        return "", err
-    }
-
-    return accessToken, nil
-}
-
-func makeRefreshToken(userId string, jwtSecret string) (refreshToken string, err error) {
-    claims := Claims{
-        UserId: userId,
-        StandardClaims: jwt.StandardClaims{
-            ExpiresAt: time.Now().Add(time.Hour * 24 * 3).Unix(),
-            Issuer:    "flexible-quest",
-        },
-    }
-
-    token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-    refreshToken, err = token.SignedString([]byte(jwtSecret))
-    if err != nil {
-        return "", err
-    }
-
-    return refreshToken, nil
-}
@@ -0,0 +1,44 @@
+// role/role.go
+package role
+
+import (
+	"guny-world-backend/api/database"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// 역할
+const (
+	// 운영자 (관리자 API 사용 가능)
+	Admin = "admin"
+)
+
+// 관리자 API 로 부여/회수할 수 있는 역할
+var known = map[string]bool{
+	Admin: true,
+}
+
+// 정의된 역할인지 확인
+func Valid(role string) bool {
+	return known[role]
+}
+
+// 계정에 부여된 역할 목록
+func List(accountId string) ([]string, error) {
+	roles := []string{}
+	err := database.DB.Select(&roles, "SELECT role FROM account_roles WHERE account_id = ? ORDER BY role", accountId)
+	return roles, err
+}
+
+// 역할 부여 (이미 있으면 그대로, 관리자 작업 기록과 같은 트랜잭션으로 넘김)
+func Grant(db sqlx.Execer, accountId, role string) error {
+	_, err := db.Exec("INSERT IGNORE INTO account_roles (account_id, role, created_at) VALUES (?, ?, ?)", accountId, role, time.Now())
+	return err
+}
+
+// 역할 회수 (이미 발급된 토큰의 roles 클레임은 남으므로 호출한 쪽에서 세션 폐기)
+func Revoke(db sqlx.Execer, accountId, role string) error {
+	_, err := db.Exec("DELETE FROM account_roles WHERE account_id = ? AND role = ?", accountId, role)
+	return err
+}
@@ -0,0 +1,62 @@
+// secretbox/secretbox.go
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"os"
+)
+
+var (
+	ErrNoKey     = errors.New("DATA_ENCRYPTION_KEY 가 설정되지 않았거나 32바이트가 아닙니다")
+	ErrMalformed = errors.New("암호문 형식이 올바르지 않습니다")
+)
+
+// DB 에 저장하는 비밀 값(OTP 시크릿, 외부 서비스 토큰 등) 암호화
+// DATA_ENCRYPTION_KEY 는 base64 로 인코딩한 32바이트 키 (AES-256-GCM)
+func Seal(plaintext []byte) (string, error) {
+	aead, err := newAEAD()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Seal 로 암호화한 값 복호화
+func Open(sealed string) ([]byte, error) {
+	aead, err := newAEAD()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newAEAD() (cipher.AEAD, error) {
+	key, err := base64.StdEncoding.DecodeString(os.Getenv("DATA_ENCRYPTION_KEY"))
+	if err != nil || len(key) != 32 {
+		return nil, ErrNoKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,122 @@
+// session/revoke.go
+package session
+
+import (
+	"database/sql"
+	"guny-world-backend/api/database"
+	"time"
+)
+
+// 리프레시 토큰이 속한 패밀리를 폐기하고 소유자 ID를 반환
+func RevokeByToken(token string) (userId string, err error) {
+	var row struct {
+		UserId   string `db:"user_id"`
+		FamilyId string `db:"family_id"`
+	}
+	err = database.DB.Get(&row, "SELECT user_id, family_id FROM refresh_tokens WHERE token_hash = ?", hashToken(token))
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	} else if err != nil {
+		return "", err
+	}
+
+	if err = RevokeFamily(row.FamilyId); err != nil {
+		return "", err
+	}
+	return row.UserId, nil
+}
+
+// 엑세스 토큰 단건 폐기 (남은 유효 기간 동안 거부)
+func RevokeAccessToken(jti, userId string, expiresAt time.Time) error {
+	_, err := database.DB.Exec("INSERT IGNORE INTO revoked_access_tokens (jti, user_id, expires_at, created_at) VALUES (?, ?, ?, ?)",
+		jti, userId, expiresAt, time.Now())
+	return err
+}
+
+// 사용자의 모든 리프레시 토큰을 폐기하고, 지금까지 발급된 엑세스 토큰도 거부되도록 기준 시각(Unix 초) 기록
+func RevokeAllForUser(userId string) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", now, userId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", now, userId)
+	if err != nil {
+		return err
+	}
+
+	// iat 와 같은 초 단위, 여러 서버의 시계가 달라도 뒤로 가지 않도록 큰 값 유지
+	_, err = tx.Exec("INSERT INTO access_token_cutoffs (user_id, revoked_through) VALUES (?, ?) ON DUPLICATE KEY UPDATE revoked_through = GREATEST(revoked_through, VALUES(revoked_through))",
+		userId, now.Unix())
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// 폐기된 엑세스 토큰인지 확인 (토큰 단건, 소속 세션, 전체 로그아웃 기준 시각 순)
+func IsAccessTokenRevoked(jti, sessionId, userId string, issuedAt int64) (bool, error) {
+	if jti != "" {
+		var count int
+		err := database.DB.Get(&count, "SELECT COUNT(*) FROM revoked_access_tokens WHERE jti = ?", jti)
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	// 세션이 없으면(익명화된 계정 등) 폐기된 것으로 봄
+	if sessionId != "" {
+		var revokedAt sql.NullTime
+		err := database.DB.Get(&revokedAt, "SELECT revoked_at FROM sessions WHERE id = ?", sessionId)
+		if err == sql.ErrNoRows {
+			return true, nil
+		} else if err != nil {
+			return false, err
+		}
+		if revokedAt.Valid {
+			return true, nil
+		}
+	}
+
+	var revokedThrough int64
+	err := database.DB.Get(&revokedThrough, "SELECT revoked_through FROM access_token_cutoffs WHERE user_id = ?", userId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	// iat 는 초 단위라 같은 초 안의 앞뒤를 구분할 수 없으므로, 전체 로그아웃과 같은 초에 발급된 토큰도 거부
+	return issuedAt <= revokedThrough, nil
+}
+
+// 현재 세션을 제외한 사용자의 모든 세션 폐기
+func RevokeOthers(userId, keepSessionId string) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND family_id <> ? AND revoked_at IS NULL", now, userId, keepSessionId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND id <> ? AND revoked_at IS NULL", now, userId, keepSessionId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,195 @@
+// session/session.go
+package session
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"guny-world-backend/api/database"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound = errors.New("리프레시 토큰을 찾을 수 없습니다")
+	ErrExpired  = errors.New("만료된 리프레시 토큰입니다")
+	ErrRevoked  = errors.New("폐기된 리프레시 토큰입니다")
+	ErrReused   = errors.New("이미 사용된 리프레시 토큰입니다")
+)
+
+// 로그인 방식 (외부 로그인은 제공자 이름을 그대로 사용)
+const (
+	MethodPassword = "password"
+	MethodNaver    = "naver"
+	MethodPasskey  = "passkey"
+)
+
+// 세션(기기) 정보
+type Session struct {
+	Id          string    `db:"id" json:"id"`
+	LoginMethod string    `db:"login_method" json:"loginMethod"`
+	UserAgent   string    `db:"user_agent" json:"userAgent"`
+	Ip          string    `db:"ip" json:"ip"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	LastUsedAt  time.Time `db:"last_used_at" json:"lastUsedAt"`
+}
+
+// 로그인 시 새 세션을 만들고 ID를 반환 (세션 ID가 곧 리프레시 토큰 패밀리 ID)
+func Start(userId, loginMethod, userAgent, ip string) (familyId string, err error) {
+	familyId = uuid.NewString()
+	now := time.Now()
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+
+	_, err = database.DB.Exec("INSERT INTO sessions (id, user_id, login_method, user_agent, ip, created_at, last_used_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		familyId, userId, loginMethod, userAgent, ip, now, now)
+	if err != nil {
+		return "", err
+	}
+	return familyId, nil
+}
+
+// 토큰 재발급 시 세션 마지막 사용 정보 갱신
+func Touch(familyId, userAgent, ip string) error {
+	if len(userAgent) > 512 {
+		userAgent = userAgent[:512]
+	}
+	_, err := database.DB.Exec("UPDATE sessions SET last_used_at = ?, user_agent = ?, ip = ? WHERE id = ?", time.Now(), userAgent, ip, familyId)
+	return err
+}
+
+// 사용자의 활성 세션 목록
+func List(userId string) ([]Session, error) {
+	sessions := []Session{}
+	err := database.DB.Select(&sessions, "SELECT id, login_method, user_agent, ip, created_at, last_used_at FROM sessions WHERE user_id = ? AND revoked_at IS NULL ORDER BY last_used_at DESC", userId)
+	return sessions, err
+}
+
+// 세션을 만든 시각 (재발급해도 바뀌지 않으므로 마지막으로 로그인한 시각)
+func StartedAt(userId, familyId string) (time.Time, error) {
+	var createdAt time.Time
+	err := database.DB.Get(&createdAt, "SELECT created_at FROM sessions WHERE id = ? AND user_id = ? AND revoked_at IS NULL", familyId, userId)
+	if err == sql.ErrNoRows {
+		return time.Time{}, ErrNotFound
+	}
+	return createdAt, err
+}
+
+// 사용자의 세션 하나를 폐기 (다른 사용자의 세션이면 ErrNotFound)
+func Revoke(userId, familyId string) error {
+	var count int
+	err := database.DB.Get(&count, "SELECT COUNT(*) FROM sessions WHERE id = ? AND user_id = ? AND revoked_at IS NULL", familyId, userId)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return RevokeFamily(familyId)
+}
+
+// 토큰 원문은 저장하지 않고 해시만 저장
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// 발급한 리프레시 토큰 저장
+func Save(userId, familyId, token string, expiresAt time.Time) error {
+	_, err := database.DB.Exec("INSERT INTO refresh_tokens (token_hash, family_id, user_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		hashToken(token), familyId, userId, expiresAt, time.Now())
+	return err
+}
+
+// 리프레시 토큰을 1회 사용 처리하고 issue 가 만든 같은 패밀리의 새 리프레시 토큰을 한 트랜잭션에서 저장
+// issue 나 저장이 실패하면 기존 토큰은 사용되지 않은 채로 남아 클라이언트가 같은 토큰으로 다시 시도할 수 있음
+// 이미 사용된 토큰이 다시 들어오면 탈취로 보고 패밀리 전체를 폐기
+func Rotate(token string, issue func(userId, familyId string) (newToken string, expiresAt time.Time, err error)) (userId string, familyId string, err error) {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	var row struct {
+		UserId    string       `db:"user_id"`
+		FamilyId  string       `db:"family_id"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+		RevokedAt sql.NullTime `db:"revoked_at"`
+	}
+	err = tx.Get(&row, "SELECT user_id, family_id, expires_at, used_at, revoked_at FROM refresh_tokens WHERE token_hash = ? FOR UPDATE", hashToken(token))
+	if err == sql.ErrNoRows {
+		return "", "", ErrNotFound
+	} else if err != nil {
+		return "", "", err
+	}
+
+	if row.RevokedAt.Valid {
+		return "", "", ErrRevoked
+	}
+
+	if row.UsedAt.Valid {
+		_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL", time.Now(), row.FamilyId)
+		if err != nil {
+			return "", "", err
+		}
+		_, err = tx.Exec("UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", time.Now(), row.FamilyId)
+		if err != nil {
+			return "", "", err
+		}
+		if err = tx.Commit(); err != nil {
+			return "", "", err
+		}
+		return "", "", ErrReused
+	}
+
+	if time.Now().After(row.ExpiresAt) {
+		return "", "", ErrExpired
+	}
+
+	newToken, expiresAt, err := issue(row.UserId, row.FamilyId)
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	_, err = tx.Exec("UPDATE refresh_tokens SET used_at = ? WHERE token_hash = ?", now, hashToken(token))
+	if err != nil {
+		return "", "", err
+	}
+	_, err = tx.Exec("INSERT INTO refresh_tokens (token_hash, family_id, user_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		hashToken(newToken), row.FamilyId, row.UserId, expiresAt, now)
+	if err != nil {
+		return "", "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", "", err
+	}
+
+	return row.UserId, row.FamilyId, nil
+}
+
+// 토큰 패밀리(세션) 전체 폐기
+func RevokeFamily(familyId string) error {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL", now, familyId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE sessions SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL", now, familyId)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,123 @@
+// storage/storage.go
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidKey = errors.New("올바르지 않은 저장 경로입니다")
+
+// 업로드 파일 저장소
+type Storage interface {
+	// key 위치에 파일 저장 (이미 있으면 덮어씀)
+	Put(key string, data []byte, contentType string) error
+	// key 위치의 파일 삭제 (없어도 오류 아님)
+	Delete(key string) error
+	// 브라우저에서 접근할 수 있는 주소
+	URL(key string) string
+}
+
+var (
+	defaultStorage Storage
+	defaultOnce    sync.Once
+)
+
+// 환경변수 STORAGE_BACKEND 에 맞는 저장소
+//
+//	s3     S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY, S3_PUBLIC_URL, S3_PATH_STYLE
+//	local  (기본값) UPLOAD_DIR 에 저장하고 UPLOAD_BASE_URL/<key> 로 제공
+func Default() Storage {
+	defaultOnce.Do(func() {
+		if os.Getenv("STORAGE_BACKEND") == "s3" {
+			defaultStorage = &S3Storage{
+				Endpoint:  os.Getenv("S3_ENDPOINT"),
+				Region:    envOr("S3_REGION", "us-east-1"),
+				Bucket:    os.Getenv("S3_BUCKET"),
+				AccessKey: os.Getenv("S3_ACCESS_KEY"),
+				SecretKey: os.Getenv("S3_SECRET_KEY"),
+				PublicURL: os.Getenv("S3_PUBLIC_URL"),
+				PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+			}
+			return
+		}
+		defaultStorage = &LocalStorage{Dir: LocalDir(), BaseURL: envOr("UPLOAD_BASE_URL", "/uploads")}
+	})
+	return defaultStorage
+}
+
+// 로컬 저장소 디렉터리 (S3 를 쓰면 빈 값, 서버가 직접 파일을 제공할지 판단하는 데 사용)
+func LocalDir() string {
+	if os.Getenv("STORAGE_BACKEND") == "s3" {
+		return ""
+	}
+	return envOr("UPLOAD_DIR", "uploads")
+}
+
+// 로컬 파일 시스템 저장소
+type LocalStorage struct {
+	Dir     string
+	BaseURL string
+}
+
+func (s *LocalStorage) Put(key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// 읽는 쪽에서 쓰다 만 파일을 보지 않도록 임시 파일에 쓴 뒤 이름 변경
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+}
+
+// 저장소 디렉터리 밖을 가리키는 key 는 거부
+func (s *LocalStorage) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
+
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
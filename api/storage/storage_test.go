@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateKey(t *testing.T) {
+	valid := []string{"a", "users/1/256.webp", "a.b/c..d", "..a/b"}
+	for _, key := range valid {
+		if err := validateKey(key); err != nil {
+			t.Errorf("validateKey(%q) = %v, want nil", key, err)
+		}
+	}
+
+	invalid := []string{"", "/abs", "/", "a\\b", "..\\x", "..", ".", "../x", "a/../b", "a/..", "./a", "a/.", "a//b", "a/"}
+	for _, key := range invalid {
+		if err := validateKey(key); err != ErrInvalidKey {
+			t.Errorf("validateKey(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestLocalStoragePutDelete(t *testing.T) {
+	dir := t.TempDir()
+	s := &LocalStorage{Dir: dir, BaseURL: "/uploads/"}
+
+	if err := s.Put("users/1/64.webp", []byte("first"), "image/webp"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put("users/1/64.webp", []byte("second"), "image/webp"); err != nil {
+		t.Fatalf("Put 덮어쓰기: %v", err)
+	}
+	path := filepath.Join(dir, "users", "1", "64.webp")
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Fatalf("저장된 파일 = %q, %v", data, err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("임시 파일이 남아 있음: %v", err)
+	}
+
+	if got := s.URL("users/1/64.webp"); got != "/uploads/users/1/64.webp" {
+		t.Errorf("URL = %q", got)
+	}
+
+	if err := s.Delete("users/1/64.webp"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("삭제 후에도 파일이 있음: %v", err)
+	}
+	// 없는 파일 삭제는 오류 아님
+	if err := s.Delete("users/1/64.webp"); err != nil {
+		t.Errorf("없는 파일 Delete = %v", err)
+	}
+}
+
+func TestLocalStorageRejectsEscape(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "uploads")
+	s := &LocalStorage{Dir: dir}
+
+	outside := filepath.Join(root, "secret")
+	if err := os.WriteFile(outside, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"../secret", "a/../../secret", "/etc/passwd", "..\\secret", ""} {
+		if err := s.Put(key, []byte("x"), "text/plain"); err != ErrInvalidKey {
+			t.Errorf("Put(%q) = %v, want ErrInvalidKey", key, err)
+		}
+		if err := s.Delete(key); err != ErrInvalidKey {
+			t.Errorf("Delete(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	if data, err := os.ReadFile(outside); err != nil || string(data) != "keep" {
+		t.Errorf("디렉터리 밖 파일이 바뀜: %q, %v", data, err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("거부된 요청에서 디렉터리가 만들어짐: %v", err)
+	}
+}
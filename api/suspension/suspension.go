@@ -0,0 +1,60 @@
+// suspension/suspension.go
+package suspension
+
+import (
+	"database/sql"
+	"guny-world-backend/api/database"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 계정 정지 정보
+type Suspension struct {
+	Reason      string
+	SuspendedAt time.Time
+	Until       *time.Time // nil 이면 영구 정지
+}
+
+// 현재 적용 중인 정지 조회 (정지되지 않았거나 기간이 끝났으면 nil)
+func Get(accountId string) (*Suspension, error) {
+	var row struct {
+		SuspendedAt    sql.NullTime   `db:"suspended_at"`
+		SuspendedUntil sql.NullTime   `db:"suspended_until"`
+		Reason         sql.NullString `db:"suspension_reason"`
+	}
+	err := database.DB.Get(&row, "SELECT suspended_at, suspended_until, suspension_reason FROM accounts WHERE id = ?", accountId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if !row.SuspendedAt.Valid {
+		return nil, nil
+	}
+	if row.SuspendedUntil.Valid && !time.Now().Before(row.SuspendedUntil.Time) {
+		return nil, nil
+	}
+
+	suspension := &Suspension{Reason: row.Reason.String, SuspendedAt: row.SuspendedAt.Time}
+	if row.SuspendedUntil.Valid {
+		until := row.SuspendedUntil.Time
+		suspension.Until = &until
+	}
+	return suspension, nil
+}
+
+// 정지된 계정의 요청 거부 (사유와 종료일을 함께 응답)
+func Reject(c *fiber.Ctx, s *Suspension) error {
+	message := "이용이 영구 정지된 계정입니다."
+	if s.Until != nil {
+		message = s.Until.Format("2006-01-02 15:04") + " 까지 이용이 정지된 계정입니다."
+	}
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"error":     message,
+		"code":      "account_suspended",
+		"reason":    s.Reason,
+		"until":     s.Until,
+		"permanent": s.Until == nil,
+	})
+}
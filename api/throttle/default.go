@@ -0,0 +1,36 @@
+// throttle/default.go
+package throttle
+
+import (
+	"math"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	defaultGuard *Guard
+	defaultOnce  sync.Once
+)
+
+// 로그인, 인증 코드, 비밀번호 확인 실패 추적기 (LOGIN_THROTTLE_STORE=mysql 이면 여러 서버가 공유)
+func Default() *Guard {
+	defaultOnce.Do(func() {
+		var store Store = NewMemoryStore()
+		if os.Getenv("LOGIN_THROTTLE_STORE") == "mysql" {
+			store = SQLStore{}
+		}
+		defaultGuard = NewGuard(store)
+	})
+	return defaultGuard
+}
+
+// 시도 제한 응답 (Retry-After 헤더 포함)
+func Reject(c *fiber.Ctx, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return c.Status(429).JSON(fiber.Map{"error": "시도 횟수가 너무 많습니다. 잠시 후 다시 시도해 주세요.", "retryAfter": seconds})
+}
@@ -0,0 +1,144 @@
+// throttle/store.go
+package throttle
+
+import (
+	"guny-world-backend/api/database"
+	"sync"
+	"time"
+)
+
+// 키 하나의 실패 기록
+type Entry struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// 실패 횟수 저장소
+// 서버 한 대면 MemoryStore, 여러 대면 모든 서버가 같이 보는 SQLStore 사용
+type Store interface {
+	// 정책상 지금 시도할 수 있으면 실패 1회를 미리 기록하고 갱신된 기록 반환
+	// 시도할 수 없으면 기록은 그대로 두고 남은 대기 시간 반환
+	// 확인과 기록이 한 번에 이뤄지므로 동시 요청이 지연을 건너뛰지 못함
+	Acquire(key string, policy Policy) (Entry, time.Duration, error)
+	// 미리 기록한 실패 1회 취소
+	Release(key string) error
+	// 기록 삭제
+	Reset(key string) error
+}
+
+// 프로세스 메모리 저장소
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	Entry
+	expiresAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+func (s *MemoryStore) Acquire(key string, policy Policy) (Entry, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if len(s.entries) > 10000 {
+		s.sweep(now)
+	}
+
+	entry := s.entries[key]
+	if now.After(entry.expiresAt) {
+		entry = memoryEntry{}
+	}
+	if wait := policy.RetryAfter(entry.Entry, now); wait > 0 {
+		return entry.Entry, wait, nil
+	}
+	entry.Failures++
+	entry.LastFailureAt = now
+	entry.expiresAt = now.Add(policy.Window)
+	s.entries[key] = entry
+	return entry.Entry, 0, nil
+}
+
+func (s *MemoryStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.Failures > 0 {
+		entry.Failures--
+		s.entries[key] = entry
+	}
+	return nil
+}
+
+func (s *MemoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// 만료된 기록 정리 (mu 를 잡은 상태에서 호출)
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// MySQL login_failures 테이블 저장소 (행 잠금으로 확인과 기록을 묶음)
+type SQLStore struct{}
+
+func (SQLStore) Acquire(key string, policy Policy) (Entry, time.Duration, error) {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	defer tx.Rollback()
+
+	// 잠글 행이 없으면 빈 기록을 먼저 만듦
+	now := time.Now()
+	_, err = tx.Exec("INSERT IGNORE INTO login_failures (throttle_key, failures, last_failure_at, expires_at) VALUES (?, 0, ?, ?)", key, now, now)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+
+	var entry Entry
+	var expiresAt time.Time
+	err = tx.QueryRow("SELECT failures, last_failure_at, expires_at FROM login_failures WHERE throttle_key = ? FOR UPDATE", key).
+		Scan(&entry.Failures, &entry.LastFailureAt, &expiresAt)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	if expiresAt.Before(now) {
+		entry = Entry{}
+	}
+	if wait := policy.RetryAfter(entry, now); wait > 0 {
+		return entry, wait, nil
+	}
+
+	entry.Failures++
+	entry.LastFailureAt = now
+	_, err = tx.Exec("UPDATE login_failures SET failures = ?, last_failure_at = ?, expires_at = ? WHERE throttle_key = ?",
+		entry.Failures, now, now.Add(policy.Window), key)
+	if err != nil {
+		return Entry{}, 0, err
+	}
+	return entry, 0, tx.Commit()
+}
+
+func (SQLStore) Release(key string) error {
+	_, err := database.DB.Exec("UPDATE login_failures SET failures = GREATEST(failures - 1, 0) WHERE throttle_key = ?", key)
+	return err
+}
+
+func (SQLStore) Reset(key string) error {
+	_, err := database.DB.Exec("DELETE FROM login_failures WHERE throttle_key = ?", key)
+	return err
+}
@@ -0,0 +1,156 @@
+// throttle/throttle.go
+package throttle
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+// 실패 횟수에 따른 지연/잠금 정책
+type Policy struct {
+	FreeAttempts int           // 지연 없이 허용하는 실패 횟수
+	BaseDelay    time.Duration // 첫 지연 (이후 실패마다 2배)
+	MaxDelay     time.Duration // 지연 상한
+	Threshold    int           // 잠금 기준 실패 횟수
+	Lockout      time.Duration // 잠금 시간
+	Window       time.Duration // 마지막 실패 후 기록 유지 기간
+}
+
+// 다음 시도까지 기다려야 하는 시간 (0 이면 바로 시도 가능)
+func (p Policy) RetryAfter(entry Entry, now time.Time) time.Duration {
+	if entry.Failures == 0 {
+		return 0
+	}
+
+	var wait time.Duration
+	if p.Threshold > 0 && entry.Failures >= p.Threshold {
+		wait = p.Lockout
+	} else if entry.Failures > p.FreeAttempts {
+		wait = p.BaseDelay
+		for i := p.FreeAttempts + 1; i < entry.Failures && wait < p.MaxDelay; i++ {
+			wait *= 2
+		}
+		if wait > p.MaxDelay {
+			wait = p.MaxDelay
+		}
+	}
+
+	remaining := entry.LastFailureAt.Add(wait).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// 계정별/IP별 로그인 실패 추적기
+// 계정 값은 이메일이나 "2fa:<id>" 처럼 용도를 붙인 계정 id
+type Guard struct {
+	Store   Store
+	Account Policy
+	IP      Policy
+	// 계정이나 IP 가 잠금 기준에 도달했을 때 호출 (kind 는 "account" 또는 "ip", 기록용)
+	OnLockout func(kind string, key string, entry Entry)
+}
+
+// 기본 정책
+// 계정: 3회까지 바로, 이후 1초부터 2배씩(최대 1분), 10회 실패 시 15분 잠금
+// IP: 여러 계정을 돌아가며 시도하는 경우를 막기 위해 30회 실패 시 15분 잠금
+func NewGuard(store Store) *Guard {
+	return &Guard{
+		Store: store,
+		Account: Policy{
+			FreeAttempts: 3,
+			BaseDelay:    time.Second,
+			MaxDelay:     time.Minute,
+			Threshold:    10,
+			Lockout:      time.Minute * 15,
+			Window:       time.Hour,
+		},
+		IP: Policy{
+			FreeAttempts: 10,
+			BaseDelay:    time.Second,
+			MaxDelay:     time.Second * 30,
+			Threshold:    30,
+			Lockout:      time.Minute * 15,
+			Window:       time.Hour,
+		},
+		OnLockout: func(kind string, key string, entry Entry) {
+			log.Printf("로그인 잠금: %s=%s 실패 %d회", kind, key, entry.Failures)
+		},
+	}
+}
+
+// 진행 중인 시도
+// 시작할 때 실패로 미리 기록하고, 결과에 따라 확정(Fail)/초기화(Succeed)/취소(Cancel)
+type Attempt struct {
+	guard        *Guard
+	account      string
+	ip           string
+	accountEntry Entry
+	ipEntry      Entry
+}
+
+// 시도 시작 (0 보다 큰 시간을 돌려주면 그 시간 동안 시도 불가)
+func (g *Guard) Begin(account, ip string) (*Attempt, time.Duration, error) {
+	account = normalizeAccount(account)
+
+	ipEntry, wait, err := g.Store.Acquire(ipKey(ip), g.IP)
+	if err != nil || wait > 0 {
+		return nil, wait, err
+	}
+
+	accountEntry, wait, err := g.Store.Acquire(accountKey(account), g.Account)
+	if err != nil || wait > 0 {
+		if err := g.Store.Release(ipKey(ip)); err != nil {
+			log.Println("IP 실패 기록 취소 에러: ", err)
+		}
+		return nil, wait, err
+	}
+
+	return &Attempt{guard: g, account: account, ip: ip, accountEntry: accountEntry, ipEntry: ipEntry}, 0, nil
+}
+
+// 실패 확정 (시작할 때 이미 기록했으므로 잠금 기준 도달 여부만 확인)
+// 이번 실패로 계정이 잠금 기준에 도달했으면 true
+func (a *Attempt) Fail() bool {
+	g := a.guard
+	if a.ipEntry.Failures == g.IP.Threshold && g.OnLockout != nil {
+		g.OnLockout("ip", a.ip, a.ipEntry)
+	}
+
+	locked := a.accountEntry.Failures == g.Account.Threshold
+	if locked && g.OnLockout != nil {
+		g.OnLockout("account", a.account, a.accountEntry)
+	}
+	return locked
+}
+
+// 성공 시 계정 기록 초기화 (IP 는 이번 시도만 취소)
+func (a *Attempt) Succeed() error {
+	if err := a.guard.Store.Reset(accountKey(a.account)); err != nil {
+		return err
+	}
+	return a.guard.Store.Release(ipKey(a.ip))
+}
+
+// 서버 오류 등으로 결과를 알 수 없는 시도는 기록에서 제외
+func (a *Attempt) Cancel() error {
+	if err := a.guard.Store.Release(accountKey(a.account)); err != nil {
+		return err
+	}
+	return a.guard.Store.Release(ipKey(a.ip))
+}
+
+// 이메일은 대소문자, 앞뒤 공백 구분 없이 같은 계정으로 취급 (MySQL 조회와 같은 기준)
+func normalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
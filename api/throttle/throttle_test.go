@@ -0,0 +1,111 @@
+package throttle
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBeginCountsConcurrentAttempts(t *testing.T) {
+	guard := NewGuard(NewMemoryStore())
+	guard.OnLockout = nil
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt, wait, err := guard.Begin("user@example.com", "127.0.0.1")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if wait == 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+				attempt.Fail()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 지연 없이 허용하는 횟수 + 첫 지연 전 1회
+	if want := guard.Account.FreeAttempts + 1; allowed != want {
+		t.Fatalf("allowed %d concurrent attempts, want %d", allowed, want)
+	}
+}
+
+func TestBeginNormalizesAccount(t *testing.T) {
+	guard := NewGuard(NewMemoryStore())
+	guard.OnLockout = nil
+	guard.Account.FreeAttempts = 0
+
+	attempt, _, err := guard.Begin("User@Example.com ", "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attempt.Fail()
+
+	_, wait, err := guard.Begin("user@example.com", "10.0.0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait == 0 {
+		t.Fatal("case variant of the same email was not throttled")
+	}
+}
+
+func TestFailReportsLockoutOnce(t *testing.T) {
+	guard := NewGuard(NewMemoryStore())
+	guard.Account = Policy{Threshold: 3, Lockout: time.Minute, Window: time.Hour}
+	var lockouts []string
+	guard.OnLockout = func(kind string, key string, entry Entry) {
+		lockouts = append(lockouts, kind+"="+key)
+	}
+
+	var locked []bool
+	for i := 0; i < 4; i++ {
+		attempt, wait, err := guard.Begin("user@example.com", "127.0.0.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait > 0 {
+			break
+		}
+		locked = append(locked, attempt.Fail())
+	}
+
+	if len(locked) != 3 || locked[0] || locked[1] || !locked[2] {
+		t.Fatalf("locked = %v, want lockout on the third failure only", locked)
+	}
+	if len(lockouts) != 1 || lockouts[0] != "account=user@example.com" {
+		t.Fatalf("lockouts = %v", lockouts)
+	}
+}
+
+func TestSucceedAndCancelRelease(t *testing.T) {
+	guard := NewGuard(NewMemoryStore())
+	guard.OnLockout = nil
+	guard.IP = Policy{Threshold: 2, Lockout: time.Minute, Window: time.Hour}
+
+	// 성공하거나 취소한 시도는 IP 실패로 남지 않음
+	for i := 0; i < 5; i++ {
+		attempt, wait, err := guard.Begin("user@example.com", "127.0.0.1")
+		if err != nil || wait > 0 {
+			t.Fatalf("attempt %d blocked: wait=%v err=%v", i, wait, err)
+		}
+		if i%2 == 0 {
+			err = attempt.Succeed()
+		} else {
+			err = attempt.Cancel()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
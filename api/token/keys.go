@@ -0,0 +1,125 @@
+// token/keys.go
+package token
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownKey = errors.New("알 수 없는 서명 키입니다")
+
+// kid 로 구분되는 RS256 서명 키 묶음
+// Active 키로 서명하고, 폐기 예정 키도 남아 있는 동안에는 검증에 사용
+type KeySet struct {
+	Active string
+	keys   map[string]*rsa.PrivateKey
+}
+
+// 디렉터리의 <kid>.pem 파일(PKCS#1 또는 PKCS#8 RSA 개인 키)을 모두 읽음
+func LoadKeySet(dir string, activeKid string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{Active: activeKid, keys: map[string]*rsa.PrivateKey{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parseRSAPrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		set.keys[kid] = key
+	}
+
+	if _, ok := set.keys[activeKid]; !ok {
+		return nil, fmt.Errorf("활성 서명 키 %q 가 %s 에 없습니다", activeKid, dir)
+	}
+	return set, nil
+}
+
+// 키 묶음 직접 구성 (테스트나 키 관리 도구에서 사용)
+func NewKeySet(activeKid string, keys map[string]*rsa.PrivateKey) (*KeySet, error) {
+	if _, ok := keys[activeKid]; !ok {
+		return nil, fmt.Errorf("활성 서명 키 %q 가 없습니다", activeKid)
+	}
+	return &KeySet{Active: activeKid, keys: keys}, nil
+}
+
+func (k *KeySet) signingKey() *rsa.PrivateKey {
+	return k.keys[k.Active]
+}
+
+func (k *KeySet) publicKey(kid string) (*rsa.PublicKey, error) {
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return &key.PublicKey, nil
+}
+
+// JWKS 공개 키 한 건
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// /.well-known/jwks.json 으로 공개할 키 목록 (kid 순 정렬)
+func (k *KeySet) JWKS() []JWK {
+	kids := make([]string, 0, len(k.keys))
+	for kid := range k.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	jwks := make([]JWK, 0, len(kids))
+	for _, kid := range kids {
+		pub := k.keys[kid].PublicKey
+		jwks = append(jwks, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: kid,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		})
+	}
+	return jwks
+}
+
+func parseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("PEM 형식이 아닙니다")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("RSA 개인 키가 아닙니다")
+	}
+	return key, nil
+}
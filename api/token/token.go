@@ -0,0 +1,256 @@
+// token/token.go
+package token
+
+import (
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+// 토큰 종류 (typ 클레임)
+const (
+	TypeAccess  = "access"
+	TypeRefresh = "refresh"
+	// 비밀번호는 맞았지만 2단계 인증 코드를 기다리는 중
+	TypeTwoFactor = "2fa_pending"
+)
+
+// 2단계 인증 대기 토큰 유효 기간
+const twoFactorTTL = time.Minute * 5
+
+var (
+	ErrInvalid   = errors.New("유효하지 않은 토큰입니다")
+	ErrWrongType = errors.New("토큰 종류가 올바르지 않습니다")
+	ErrNoSecret  = errors.New("JWT 시크릿 키가 설정되지 않았습니다")
+	// RS256 전환 후 허용 기간이 지났거나 전환 이후에 발급된 HS256 토큰
+	ErrLegacyToken = errors.New("더 이상 허용되지 않는 HS256 토큰입니다")
+)
+
+// guny-world 에서 발급하는 JWT 클레임
+type Claims struct {
+	UserId    string   `json:"user_id"`
+	SessionId string   `json:"sid,omitempty"`
+	TokenType string   `json:"typ"`
+	Roles     []string `json:"roles,omitempty"`
+	// 2단계 인증 대기 토큰에만 사용 (인증을 마친 뒤 만들 세션의 로그인 방식)
+	LoginMethod string `json:"login_method,omitempty"`
+	jwt.StandardClaims
+}
+
+// 토큰 발급/검증 설정
+// Keys 가 있으면 RS256 으로 서명하고, Secret 은 키가 없는 로컬 환경의 서명과 전환 기간의 기존 HS256 토큰 검증에만 사용
+type Config struct {
+	Keys   *KeySet
+	Secret []byte
+	// RS256 전환 시각 (Keys 가 있을 때 이 시각 이전에 발급된 HS256 토큰만, 이 시각부터 RefreshTTL 동안만 검증)
+	// 비어 있으면 Keys 가 있을 때 HS256 토큰은 모두 거부
+	LegacyCutover time.Time
+	Issuer        string
+	Audience      string
+	AccessTTL     time.Duration
+	RefreshTTL    time.Duration
+}
+
+// 토큰 발급/검증기
+type Service struct {
+	Config Config
+	Now    func() time.Time
+}
+
+// 환경변수에서 설정 읽기
+//
+//	JWT_KEYS_DIR       RS256 서명 키 디렉터리 (<kid>.pem)
+//	JWT_ACTIVE_KID     서명에 사용할 kid
+//	JWT_SECRET_TOKEN   HS256 키 (키 디렉터리가 없을 때 서명, 전환 기간 동안 기존 토큰 검증)
+//	JWT_HS256_CUTOVER  RS256 전환 시각 (RFC3339, 비우면 키 디렉터리가 있을 때 HS256 토큰 거부)
+//	JWT_ISSUER         발급자 (기본값 guny-world)
+//	JWT_AUDIENCE       대상 (기본값 guny-world)
+//	JWT_ACCESS_TTL     엑세스 토큰 유효 기간 (기본값 1h)
+//	JWT_REFRESH_TTL    리프레시 토큰 유효 기간 (기본값 72h)
+func LoadConfig() (Config, error) {
+	config := Config{
+		Secret:     []byte(os.Getenv("JWT_SECRET_TOKEN")),
+		Issuer:     envOr("JWT_ISSUER", "guny-world"),
+		Audience:   envOr("JWT_AUDIENCE", "guny-world"),
+		AccessTTL:  durationEnvOr("JWT_ACCESS_TTL", time.Hour),
+		RefreshTTL: durationEnvOr("JWT_REFRESH_TTL", time.Hour*24*3),
+	}
+
+	if cutover := os.Getenv("JWT_HS256_CUTOVER"); cutover != "" {
+		t, err := time.Parse(time.RFC3339, cutover)
+		if err != nil {
+			return Config{}, err
+		}
+		config.LegacyCutover = t
+	}
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		keys, err := LoadKeySet(dir, os.Getenv("JWT_ACTIVE_KID"))
+		if err != nil {
+			return Config{}, err
+		}
+		config.Keys = keys
+	}
+
+	return config, nil
+}
+
+func NewService(config Config) *Service {
+	return &Service{Config: config, Now: time.Now}
+}
+
+var (
+	defaultService *Service
+	defaultOnce    sync.Once
+)
+
+// 환경변수 설정으로 기본 발급기 초기화 (서버 시작 시 호출, 키 교체는 재시작으로 반영)
+func Init() {
+	defaultOnce.Do(func() {
+		config, err := LoadConfig()
+		if err != nil {
+			log.Fatal("JWT 설정 로드 실패: ", err)
+		}
+		defaultService = NewService(config)
+	})
+}
+
+// 기본 발급기
+func Default() *Service {
+	Init()
+	return defaultService
+}
+
+// 엑세스 토큰 발급 (역할은 발급 시점 기준, 바뀐 역할은 다음 재발급부터 반영)
+func (s *Service) NewAccessToken(userId, sessionId string, roles []string) (string, error) {
+	signed, _, err := s.sign(Claims{UserId: userId, SessionId: sessionId, TokenType: TypeAccess, Roles: roles}, s.Config.AccessTTL)
+	return signed, err
+}
+
+// 리프레시 토큰 발급 (저장소에 기록할 만료 시각도 함께 반환)
+func (s *Service) NewRefreshToken(userId, sessionId string) (string, time.Time, error) {
+	return s.sign(Claims{UserId: userId, SessionId: sessionId, TokenType: TypeRefresh}, s.Config.RefreshTTL)
+}
+
+// 2단계 인증 대기 토큰 발급 (인증 코드 확인 엔드포인트에서만 사용 가능)
+// loginMethod 는 1단계 로그인 방식 (비밀번호, 외부 로그인, 패스키)
+func (s *Service) NewTwoFactorToken(userId, loginMethod string) (string, error) {
+	signed, _, err := s.sign(Claims{UserId: userId, TokenType: TypeTwoFactor, LoginMethod: loginMethod}, twoFactorTTL)
+	return signed, err
+}
+
+// 사용자/세션/종류 등을 채운 claims 에 표준 클레임을 더해 서명
+func (s *Service) sign(claims Claims, ttl time.Duration) (string, time.Time, error) {
+	if s.Config.Keys == nil && len(s.Config.Secret) == 0 {
+		return "", time.Time{}, ErrNoSecret
+	}
+
+	now := s.Now()
+	expiresAt := now.Add(ttl)
+	claims.StandardClaims = jwt.StandardClaims{
+		Id:        uuid.NewString(),
+		Issuer:    s.Config.Issuer,
+		Audience:  s.Config.Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	}
+
+	var signed string
+	var err error
+	if s.Config.Keys != nil {
+		t := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		t.Header["kid"] = s.Config.Keys.Active
+		signed, err = t.SignedString(s.Config.Keys.signingKey())
+	} else {
+		signed, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.Config.Secret)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// 토큰 서명/알고리즘/만료/발급자/대상/종류 검증 후 클레임 반환
+func (s *Service) Parse(tokenString, tokenType string) (*Claims, error) {
+	// 시간 검증은 jwt-go 의 현재 시각 대신 s.Now 기준으로 직접 처리
+	parser := jwt.Parser{ValidMethods: []string{"RS256", "HS256"}, SkipClaimsValidation: true}
+	parsed, err := parser.ParseWithClaims(tokenString, &Claims{}, s.verificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := parsed.Claims.(*Claims)
+	if !ok || !parsed.Valid {
+		return nil, ErrInvalid
+	}
+
+	// jwt-go 는 exp 가 없으면 통과시키므로 직접 확인
+	now := s.Now().Unix()
+	if claims.ExpiresAt == 0 || !claims.VerifyExpiresAt(now, true) || !claims.VerifyNotBefore(now, false) {
+		return nil, ErrInvalid
+	}
+	if !claims.VerifyIssuer(s.Config.Issuer, true) || !claims.VerifyAudience(s.Config.Audience, true) {
+		return nil, ErrInvalid
+	}
+	if claims.UserId == "" {
+		return nil, ErrInvalid
+	}
+	if claims.TokenType != tokenType {
+		return nil, ErrWrongType
+	}
+
+	return claims, nil
+}
+
+// 헤더의 alg/kid 에 맞는 검증 키 선택 (허용하지 않는 알고리즘은 거부)
+func (s *Service) verificationKey(t *jwt.Token) (interface{}, error) {
+	switch t.Method {
+	case jwt.SigningMethodRS256:
+		if s.Config.Keys == nil {
+			return nil, ErrUnknownKey
+		}
+		kid, _ := t.Header["kid"].(string)
+		return s.Config.Keys.publicKey(kid)
+	case jwt.SigningMethodHS256:
+		if len(s.Config.Secret) == 0 {
+			return nil, ErrInvalid
+		}
+		if s.Config.Keys != nil {
+			claims, ok := t.Claims.(*Claims)
+			if !ok || !s.legacyAllowed(claims) {
+				return nil, ErrLegacyToken
+			}
+		}
+		return s.Config.Secret, nil
+	}
+	return nil, ErrInvalid
+}
+
+// RS256 전환 후 기존 HS256 토큰 허용 여부
+// 시크릿을 가진 쪽이 iat 를 과거로 꾸며도 전환 시각부터 RefreshTTL 이 지나면 모두 거부
+func (s *Service) legacyAllowed(claims *Claims) bool {
+	cutover := s.Config.LegacyCutover
+	if cutover.IsZero() || !s.Now().Before(cutover.Add(s.Config.RefreshTTL)) {
+		return false
+	}
+	return claims.IssuedAt != 0 && claims.IssuedAt < cutover.Unix()
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func durationEnvOr(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
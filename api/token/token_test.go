@@ -0,0 +1,347 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var testNow = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testService(t *testing.T, keys map[string]*rsa.PrivateKey, active string) *Service {
+	t.Helper()
+	set, err := NewKeySet(active, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewService(Config{
+		Keys:       set,
+		Issuer:     "guny-world",
+		Audience:   "guny-world",
+		AccessTTL:  time.Hour,
+		RefreshTTL: time.Hour * 72,
+	})
+	service.Now = func() time.Time { return testNow }
+	return service
+}
+
+// 서명 없이 클레임만 바꾼 토큰
+func replacePayload(t *testing.T, signed string, from, to string) string {
+	t.Helper()
+	parts := strings.Split(signed, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced := strings.Replace(string(payload), from, to, 1)
+	if replaced == string(payload) {
+		t.Fatalf("payload %s does not contain %s", payload, from)
+	}
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(replaced))
+	return strings.Join(parts, ".")
+}
+
+func TestParseAccessToken(t *testing.T) {
+	service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+
+	signed, err := service.NewAccessToken("42", "session-1", []string{"admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := service.Parse(signed, TypeAccess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserId != "42" || claims.SessionId != "session-1" || len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	if claims.IssuedAt != testNow.Unix() || claims.ExpiresAt != testNow.Add(time.Hour).Unix() {
+		t.Fatalf("iat/exp not taken from Now: %d %d", claims.IssuedAt, claims.ExpiresAt)
+	}
+}
+
+func TestParseExpired(t *testing.T) {
+	service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+	signed, err := service.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.Now = func() time.Time { return testNow.Add(time.Hour) }
+	if _, err := service.Parse(signed, TypeAccess); err != nil {
+		t.Fatalf("token rejected at its expiry second: %v", err)
+	}
+
+	service.Now = func() time.Time { return testNow.Add(time.Hour + time.Second) }
+	if _, err := service.Parse(signed, TypeAccess); err == nil {
+		t.Fatal("expired token accepted")
+	}
+}
+
+func TestParseTampered(t *testing.T) {
+	service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+	signed, err := service.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("payload", func(t *testing.T) {
+		tampered := replacePayload(t, signed, `"user_id":"42"`, `"user_id":"1"`)
+		if _, err := service.Parse(tampered, TypeAccess); err == nil {
+			t.Fatal("token with modified payload accepted")
+		}
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		parts := strings.Split(signed, ".")
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature[len(signature)/2] ^= 0x01
+		parts[2] = base64.RawURLEncoding.EncodeToString(signature)
+		if _, err := service.Parse(strings.Join(parts, "."), TypeAccess); err == nil {
+			t.Fatal("token with modified signature accepted")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, value := range []string{"", "abc", signed + ".x", strings.Join(strings.Split(signed, ".")[:2], ".")} {
+			if _, err := service.Parse(value, TypeAccess); err == nil {
+				t.Fatalf("malformed token %q accepted", value)
+			}
+		}
+	})
+}
+
+func TestParseWrongType(t *testing.T) {
+	service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+
+	refresh, _, err := service.NewRefreshToken("42", "session-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Parse(refresh, TypeAccess); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("refresh token as access token: got %v, want ErrWrongType", err)
+	}
+
+	pending, err := service.NewTwoFactorToken("42", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Parse(pending, TypeAccess); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("2fa token as access token: got %v, want ErrWrongType", err)
+	}
+	if claims, err := service.Parse(pending, TypeTwoFactor); err != nil || claims.LoginMethod != "password" {
+		t.Fatalf("2fa token: claims %+v, err %v", claims, err)
+	}
+
+	access, err := service.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Parse(access, TypeRefresh); !errors.Is(err, ErrWrongType) {
+		t.Fatalf("access token as refresh token: got %v, want ErrWrongType", err)
+	}
+}
+
+func TestParseWrongAlgorithm(t *testing.T) {
+	key := testKey(t)
+	service := testService(t, map[string]*rsa.PrivateKey{"k1": key}, "k1")
+	claims := Claims{
+		UserId:    "42",
+		TokenType: TypeAccess,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    "guny-world",
+			Audience:  "guny-world",
+			IssuedAt:  testNow.Unix(),
+			ExpiresAt: testNow.Add(time.Hour).Unix(),
+		},
+	}
+
+	t.Run("none", func(t *testing.T) {
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Parse(unsigned, TypeAccess); err == nil {
+			t.Fatal("alg=none token accepted")
+		}
+	})
+
+	t.Run("hs256 with public key", func(t *testing.T) {
+		// 공개 키를 HMAC 키로 쓰는 알고리즘 혼동 공격
+		jwks := service.Config.Keys.JWKS()
+		forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(jwks[0].N))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Parse(forged, TypeAccess); err == nil {
+			t.Fatal("HS256 token accepted without a configured secret")
+		}
+	})
+
+	t.Run("rs512", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS512, claims)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Parse(signed, TypeAccess); err == nil {
+			t.Fatal("RS512 token accepted")
+		}
+	})
+
+	t.Run("missing exp", func(t *testing.T) {
+		noExpiry := claims
+		noExpiry.ExpiresAt = 0
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, noExpiry)
+		token.Header["kid"] = "k1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Parse(signed, TypeAccess); err == nil {
+			t.Fatal("token without exp accepted")
+		}
+	})
+}
+
+func TestParseWrongIssuerAndAudience(t *testing.T) {
+	keys := map[string]*rsa.PrivateKey{"k1": testKey(t)}
+	service := testService(t, keys, "k1")
+
+	other := testService(t, keys, "k1")
+	other.Config.Issuer = "someone-else"
+	signed, err := other.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Parse(signed, TypeAccess); err == nil {
+		t.Fatal("token from another issuer accepted")
+	}
+
+	other = testService(t, keys, "k1")
+	other.Config.Audience = "another-service"
+	signed, err = other.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Parse(signed, TypeAccess); err == nil {
+		t.Fatal("token for another audience accepted")
+	}
+}
+
+func TestParseKeyRotation(t *testing.T) {
+	oldKey, newKey := testKey(t), testKey(t)
+
+	before := testService(t, map[string]*rsa.PrivateKey{"old": oldKey}, "old")
+	signed, err := before.NewAccessToken("42", "session-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 폐기 예정 키가 남아 있는 동안에는 검증 가능
+	rotated := testService(t, map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey}, "new")
+	if _, err := rotated.Parse(signed, TypeAccess); err != nil {
+		t.Fatalf("token signed with retiring key rejected: %v", err)
+	}
+
+	// 키가 빠지면 거부
+	retired := testService(t, map[string]*rsa.PrivateKey{"new": newKey}, "new")
+	if _, err := retired.Parse(signed, TypeAccess); err == nil {
+		t.Fatal("token signed with removed key accepted")
+	}
+}
+
+// 공유 시크릿으로 서명한 기존 HS256 토큰
+func legacyToken(t *testing.T, secret []byte, issuedAt time.Time) string {
+	t.Helper()
+	claims := Claims{
+		UserId:    "42",
+		TokenType: TypeAccess,
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    "guny-world",
+			Audience:  "guny-world",
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: testNow.Add(time.Hour * 24 * 365).Unix(),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// jwt-go 는 키 선택 함수의 오류를 ValidationError.Inner 에 담아 반환
+func isLegacyRejection(err error) bool {
+	var validation *jwt.ValidationError
+	return errors.As(err, &validation) && validation.Inner == ErrLegacyToken
+}
+
+func TestParseLegacyHS256(t *testing.T) {
+	secret := []byte("old-shared-secret")
+
+	t.Run("without keys", func(t *testing.T) {
+		// 키 디렉터리가 없는 로컬 환경은 HS256 으로 발급/검증
+		service := NewService(Config{Secret: secret, Issuer: "guny-world", Audience: "guny-world", AccessTTL: time.Hour})
+		service.Now = func() time.Time { return testNow }
+		signed, err := service.NewAccessToken("42", "session-1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := service.Parse(signed, TypeAccess); err != nil {
+			t.Fatalf("HS256 token rejected without keys: %v", err)
+		}
+	})
+
+	t.Run("keys without cutover", func(t *testing.T) {
+		service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+		service.Config.Secret = secret
+		if _, err := service.Parse(legacyToken(t, secret, testNow.Add(-time.Minute)), TypeAccess); !isLegacyRejection(err) {
+			t.Fatalf("err = %v, want ErrLegacyToken", err)
+		}
+	})
+
+	t.Run("cutover window", func(t *testing.T) {
+		service := testService(t, map[string]*rsa.PrivateKey{"k1": testKey(t)}, "k1")
+		service.Config.Secret = secret
+		cutover := testNow.Add(-time.Hour)
+		service.Config.LegacyCutover = cutover
+
+		// 전환 이전에 발급된 토큰은 기간 안에서 허용
+		if _, err := service.Parse(legacyToken(t, secret, cutover.Add(-time.Second)), TypeAccess); err != nil {
+			t.Fatalf("token issued before cutover rejected: %v", err)
+		}
+
+		// 전환 이후에 시크릿으로 새로 서명한 토큰은 거부
+		for _, issuedAt := range []time.Time{cutover, testNow} {
+			if _, err := service.Parse(legacyToken(t, secret, issuedAt), TypeAccess); !isLegacyRejection(err) {
+				t.Fatalf("token issued at %v: err = %v, want ErrLegacyToken", issuedAt, err)
+			}
+		}
+
+		// 전환 후 RefreshTTL 이 지나면 iat 를 과거로 꾸며도 거부
+		service.Now = func() time.Time { return cutover.Add(service.Config.RefreshTTL) }
+		if _, err := service.Parse(legacyToken(t, secret, cutover.Add(-time.Hour*24*30)), TypeAccess); !isLegacyRejection(err) {
+			t.Fatalf("err = %v, want ErrLegacyToken after the transition window", err)
+		}
+	})
+}
@@ -0,0 +1,287 @@
+// twofactor/twofactor.go
+package twofactor
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"guny-world-backend/api/secretbox"
+	"guny-world-backend/api/throttle"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 발급하는 복구 코드 개수
+const recoveryCodeCount = 10
+
+// 인증 코드 실패 추적 키 (로그인과 등록/해제에서 같은 기록 사용)
+func AttemptKey(userId string) string {
+	return "2fa:" + userId
+}
+
+// 2단계 인증이 켜져 있는지 확인
+func Enabled(userId string) (bool, error) {
+	var count int
+	err := database.DB.Get(&count, "SELECT COUNT(*) FROM user_totp WHERE user_id = ? AND confirmed_at IS NOT NULL", userId)
+	return count > 0, err
+}
+
+// 인증 앱 코드 또는 복구 코드 확인 (둘 다 한 번만 사용 가능)
+func Verify(userId, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != digits {
+		return useRecoveryCode(userId, code)
+	}
+
+	var row struct {
+		Secret       string `db:"secret"`
+		LastUsedStep int64  `db:"last_used_step"`
+	}
+	err := database.DB.Get(&row, "SELECT secret, last_used_step FROM user_totp WHERE user_id = ? AND confirmed_at IS NOT NULL", userId)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	secret, err := secretbox.Open(row.Secret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := matchStep(string(secret), code, time.Now())
+	if !ok || step <= row.LastUsedStep {
+		return false, nil
+	}
+
+	// 같은 코드를 두 번 쓰지 못하도록 마지막 사용 구간 갱신
+	result, err := database.DB.Exec("UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?", step, userId, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func useRecoveryCode(userId, code string) (bool, error) {
+	result, err := database.DB.Exec("UPDATE totp_recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL LIMIT 1",
+		time.Now(), userId, onetime.Hash(normalizeRecoveryCode(code)))
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// 대소문자, 하이픈, 공백 차이 무시
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
+
+// xxxx-xxxx-xxxx 형식 복구 코드
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(buf)
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12], nil
+}
+
+// 2단계 인증 등록 시작 핸들러 (auth.RequireAuth 뒤에서 동작)
+// 인증 앱에 등록할 시크릿과 QR 코드용 URI 를 돌려주고, 확인 전까지는 로그인에 적용하지 않음
+func Enroll(c *fiber.Ctx) (err error) {
+	db := database.DB
+	userId := auth.GetPrincipal(c).UserId
+
+	// 비밀번호 계정만 사용 가능
+	var email string
+	err = db.Get(&email, "SELECT email FROM accounts WHERE id = ? AND password IS NOT NULL", userId)
+	if err == sql.ErrNoRows {
+		return c.Status(400).JSON(fiber.Map{"error": "비밀번호로 가입한 계정만 2단계 인증을 사용할 수 있습니다."})
+	} else if err != nil {
+		log.Println("사용자 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	enabled, err := Enabled(userId)
+	if err != nil {
+		log.Println("2단계 인증 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if enabled {
+		return c.Status(409).JSON(fiber.Map{"error": "이미 2단계 인증을 사용 중입니다."})
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		log.Println("OTP 시크릿 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	sealed, err := secretbox.Seal([]byte(secret))
+	if err != nil {
+		log.Println("OTP 시크릿 암호화 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 설정 오류입니다. 관리자에게 문의하세요."})
+	}
+
+	_, err = db.Exec(`INSERT INTO user_totp (user_id, secret, confirmed_at, last_used_step, created_at) VALUES (?, ?, NULL, 0, ?)
+		ON DUPLICATE KEY UPDATE secret = VALUES(secret), last_used_step = 0, created_at = VALUES(created_at)`, userId, sealed, time.Now())
+	if err != nil {
+		log.Println("OTP 시크릿 저장 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"secret": secret, "otpauthUri": provisioningURI(secret, email)})
+}
+
+// 2단계 인증 등록 확인 핸들러 (auth.RequireAuth 뒤에서 동작)
+// 인증 앱 코드가 맞으면 활성화하고 복구 코드를 한 번만 보여줌
+func ConfirmEnrollment(c *fiber.Ctx) (err error) {
+	db := database.DB
+	userId := auth.GetPrincipal(c).UserId
+
+	type RequestQuery struct {
+		Code string `json:"code"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.Code == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "인증 코드 값이 존재하지 않습니다."})
+	}
+
+	var sealed string
+	err = db.Get(&sealed, "SELECT secret FROM user_totp WHERE user_id = ? AND confirmed_at IS NULL", userId)
+	if err == sql.ErrNoRows {
+		return c.Status(400).JSON(fiber.Map{"error": "등록 중인 2단계 인증이 없습니다."})
+	} else if err != nil {
+		log.Println("OTP 시크릿 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	secret, err := secretbox.Open(sealed)
+	if err != nil {
+		log.Println("OTP 시크릿 복호화 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 코드 대입 방지 (로그인의 2단계 인증과 같은 기록 사용)
+	attempt, retryAfter, err := throttle.Default().Begin(AttemptKey(userId), c.IP())
+	if err != nil {
+		log.Println("인증 코드 실패 기록 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if retryAfter > 0 {
+		return throttle.Reject(c, retryAfter)
+	}
+
+	step, ok := matchStep(string(secret), requestQuery.Code, time.Now())
+	if !ok {
+		attempt.Fail()
+		return c.Status(400).JSON(fiber.Map{"error": "인증 코드가 일치하지 않습니다."})
+	}
+	if err := attempt.Succeed(); err != nil {
+		log.Println("인증 코드 실패 기록 초기화 에러: ", err)
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			log.Println("복구 코드 생성 실패: ", err)
+			return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+		}
+		codes = append(codes, code)
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		log.Println("트랜잭션 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	if _, err = tx.Exec("UPDATE user_totp SET confirmed_at = ?, last_used_step = ? WHERE user_id = ?", now, step, userId); err != nil {
+		log.Println("2단계 인증 활성화 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if _, err = tx.Exec("DELETE FROM totp_recovery_codes WHERE user_id = ?", userId); err != nil {
+		log.Println("기존 복구 코드 삭제 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	for _, code := range codes {
+		_, err = tx.Exec("INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)", userId, onetime.Hash(normalizeRecoveryCode(code)), now)
+		if err != nil {
+			log.Println("복구 코드 저장 실패: ", err)
+			return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		log.Println("트랜잭션 커밋 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "2단계 인증이 활성화되었습니다.", "recoveryCodes": codes})
+}
+
+// 2단계 인증 해제 핸들러 (auth.RequireAuth 뒤에서 동작, 인증 앱 코드나 복구 코드 필요)
+func Disable(c *fiber.Ctx) (err error) {
+	db := database.DB
+	userId := auth.GetPrincipal(c).UserId
+
+	type RequestQuery struct {
+		Code string `json:"code"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.Code == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "인증 코드 값이 존재하지 않습니다."})
+	}
+
+	// 탈취한 엑세스 토큰으로 코드를 대입해 2단계 인증을 끄지 못하도록 제한
+	attempt, retryAfter, err := throttle.Default().Begin(AttemptKey(userId), c.IP())
+	if err != nil {
+		log.Println("인증 코드 실패 기록 조회 에러: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if retryAfter > 0 {
+		return throttle.Reject(c, retryAfter)
+	}
+
+	ok, err := Verify(userId, requestQuery.Code)
+	if err != nil {
+		log.Println("2단계 인증 코드 확인 실패: ", err)
+		if err := attempt.Cancel(); err != nil {
+			log.Println("인증 코드 실패 기록 취소 에러: ", err)
+		}
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if !ok {
+		attempt.Fail()
+		return c.Status(400).JSON(fiber.Map{"error": "인증 코드가 일치하지 않습니다."})
+	}
+	if err := attempt.Succeed(); err != nil {
+		log.Println("인증 코드 실패 기록 초기화 에러: ", err)
+	}
+
+	if _, err = db.Exec("DELETE FROM user_totp WHERE user_id = ?", userId); err != nil {
+		log.Println("2단계 인증 해제 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	if _, err = db.Exec("DELETE FROM totp_recovery_codes WHERE user_id = ?", userId); err != nil {
+		log.Println("복구 코드 삭제 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "2단계 인증이 해제되었습니다."})
+}
@@ -0,0 +1,164 @@
+// verification/verification.go
+package verification
+
+import (
+	"database/sql"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/mail"
+	"guny-world-backend/api/onetime"
+	"log"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// 인증 링크 유효 기간
+	tokenTTL = time.Hour * 24
+	// 재발송 최소 간격
+	resendInterval = time.Minute
+	// 하루 최대 발송 횟수
+	dailyLimit = 5
+)
+
+// 인증 메일 발송 (회원가입, 재발송에서 사용)
+func Send(userId string, email string) error {
+	token, hash, err := onetime.New()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = database.DB.Exec("INSERT INTO email_verifications (token_hash, user_id, email, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		hash, userId, email, now.Add(tokenTTL), now)
+	if err != nil {
+		return err
+	}
+
+	link := os.Getenv("FRONTEND_URL") + "/verify-email?token=" + url.QueryEscape(token)
+	return mail.Default().Send(mail.Message{
+		To:      email,
+		Subject: "[거니월드] 이메일 인증을 완료해 주세요",
+		Body:    "아래 링크를 눌러 이메일 인증을 완료해 주세요. 링크는 24시간 동안 유효합니다.\n\n" + link + "\n\n본인이 가입하지 않았다면 이 메일을 무시해 주세요.",
+	})
+}
+
+// 이메일 인증 확인 핸들러
+func Confirm(c *fiber.Ctx) (err error) {
+	type RequestQuery struct {
+		Token string `json:"token"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.Token == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "인증 토큰 값이 존재하지 않습니다."})
+	}
+
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		log.Println("트랜잭션 시작 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	defer tx.Rollback()
+
+	var row struct {
+		UserId    string       `db:"user_id"`
+		ExpiresAt time.Time    `db:"expires_at"`
+		UsedAt    sql.NullTime `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT user_id, expires_at, used_at FROM email_verifications WHERE token_hash = ? FOR UPDATE", onetime.Hash(requestQuery.Token))
+	if err == sql.ErrNoRows {
+		return c.Status(400).JSON(fiber.Map{"error": "유효하지 않은 인증 링크입니다."})
+	} else if err != nil {
+		log.Println("인증 토큰 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	if row.UsedAt.Valid || time.Now().After(row.ExpiresAt) {
+		return c.Status(400).JSON(fiber.Map{"error": "만료되었거나 이미 사용된 인증 링크입니다."})
+	}
+
+	now := time.Now()
+	if _, err = tx.Exec("UPDATE accounts SET email_verified_at = ? WHERE id = ? AND email_verified_at IS NULL", now, row.UserId); err != nil {
+		log.Println("이메일 인증 처리 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 같은 사용자에게 발송된 다른 링크도 함께 사용 처리
+	if _, err = tx.Exec("UPDATE email_verifications SET used_at = ? WHERE user_id = ? AND used_at IS NULL", now, row.UserId); err != nil {
+		log.Println("인증 토큰 사용 처리 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println("트랜잭션 커밋 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "이메일 인증이 완료되었습니다."})
+}
+
+// 인증 메일 재발송 핸들러
+func Resend(c *fiber.Ctx) (err error) {
+	db := database.DB
+
+	type RequestQuery struct {
+		UserId string `json:"user_id"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil || requestQuery.UserId == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "아이디 값이 존재하지 않습니다."})
+	}
+
+	// 인증이 필요한 계정이 아니면 같은 응답만 돌려줌
+	var id string
+	err = db.Get(&id, "SELECT id FROM accounts WHERE email = ? AND email_verified_at IS NULL", requestQuery.UserId)
+	if err == sql.ErrNoRows {
+		return c.Status(200).JSON(fiber.Map{"message": "인증 메일을 발송했습니다."})
+	} else if err != nil {
+		log.Println("사용자 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	// 재발송 제한 (최소 간격, 하루 최대 횟수)
+	var recent struct {
+		Count   int          `db:"count"`
+		FirstAt sql.NullTime `db:"first_at"`
+		LastAt  sql.NullTime `db:"last_at"`
+	}
+	err = db.Get(&recent, "SELECT COUNT(*) AS count, MIN(created_at) AS first_at, MAX(created_at) AS last_at FROM email_verifications WHERE user_id = ? AND created_at > ?", id, time.Now().Add(-time.Hour*24))
+	if err != nil {
+		log.Println("발송 이력 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	if recent.Count >= dailyLimit {
+		return tooManyRequests(c, time.Until(recent.FirstAt.Time.Add(time.Hour*24)))
+	}
+	if recent.LastAt.Valid && time.Since(recent.LastAt.Time) < resendInterval {
+		return tooManyRequests(c, resendInterval-time.Since(recent.LastAt.Time))
+	}
+
+	if err := Send(id, requestQuery.UserId); err != nil {
+		log.Println("인증 메일 발송 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "인증 메일을 발송하지 못했습니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "인증 메일을 발송했습니다."})
+}
+
+func tooManyRequests(c *fiber.Ctx, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+	return c.Status(429).JSON(fiber.Map{"error": "잠시 후 다시 시도해 주세요.", "retryAfter": seconds})
+}
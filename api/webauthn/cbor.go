@@ -0,0 +1,140 @@
+// webauthn/cbor.go
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+var errCBOR = errors.New("CBOR 형식이 올바르지 않습니다")
+
+// WebAuthn 에 필요한 만큼만 지원하는 CBOR 디코더
+// 길이가 정해진 정수/바이트열/문자열/배열/맵과 true/false/null 만 처리 (CTAP2 정규 인코딩 기준)
+// 정수는 int64, 맵은 map[interface{}]interface{} (키는 int64 또는 string) 로 돌려줌
+func decodeCBOR(data []byte) (value interface{}, rest []byte, err error) {
+	d := &cborDecoder{data: data}
+	value, err = d.value(0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return value, data[d.pos:], nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) value(depth int) (interface{}, error) {
+	if depth > 16 || d.pos >= len(d.data) {
+		return nil, errCBOR
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+	major, info := initial>>5, initial&0x1f
+
+	arg, err := d.argument(info)
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return nil, errCBOR
+		}
+		return int64(arg), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			return nil, errCBOR
+		}
+		return -1 - int64(arg), nil
+	case 2, 3:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBOR
+		}
+		raw := d.data[d.pos : d.pos+int(arg)]
+		d.pos += int(arg)
+		if major == 3 {
+			return string(raw), nil
+		}
+		return append([]byte(nil), raw...), nil
+	case 4:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBOR
+		}
+		items := make([]interface{}, 0, int(arg))
+		for i := uint64(0); i < arg; i++ {
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case 5:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, errCBOR
+		}
+		m := make(map[interface{}]interface{}, int(arg))
+		for i := uint64(0); i < arg; i++ {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errCBOR
+			}
+			item, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = item
+		}
+		return m, nil
+	case 7:
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 25, 26, 27:
+			// 부동소수점은 WebAuthn 에서 쓰지 않으므로 값은 버림
+			return nil, nil
+		}
+	}
+	return nil, errCBOR
+}
+
+// 헤더 뒤에 붙는 길이/값 인자 (무한 길이 인코딩은 지원하지 않음)
+func (d *cborDecoder) argument(info byte) (uint64, error) {
+	size := 0
+	switch {
+	case info < 24:
+		return uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, errCBOR
+	}
+
+	if d.pos+size > len(d.data) {
+		return 0, errCBOR
+	}
+	buf := make([]byte, 8)
+	copy(buf[8-size:], d.data[d.pos:d.pos+size])
+	d.pos += size
+	return binary.BigEndian.Uint64(buf), nil
+}
@@ -0,0 +1,137 @@
+// webauthn/store.go
+package webauthn
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"guny-world-backend/api/database"
+	"guny-world-backend/api/onetime"
+	"time"
+)
+
+// 챌린지 유효 기간
+const challengeTTL = time.Minute * 5
+
+// 챌린지 종류
+const (
+	ceremonyRegister = "register"
+	ceremonyLogin    = "login"
+)
+
+var (
+	ErrChallengeNotFound  = errors.New("만료되었거나 이미 사용된 챌린지입니다")
+	ErrCredentialNotFound = errors.New("등록되지 않은 패스키입니다")
+)
+
+// 새 챌린지를 만들어 저장 (로그인은 사용자를 모를 수 있으므로 userId 가 비어도 됨)
+func newChallenge(ceremony, userId string) ([]byte, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	var owner sql.NullString
+	if userId != "" {
+		owner = sql.NullString{String: userId, Valid: true}
+	}
+
+	now := time.Now()
+	_, err := database.DB.Exec("INSERT INTO webauthn_challenges (challenge_hash, ceremony, user_id, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		onetime.Hash(encodeBase64URL(challenge)), ceremony, owner, now.Add(challengeTTL), now)
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// 챌린지를 1회 사용 처리하고 발급 당시의 사용자 ID 반환
+func consumeChallenge(challenge []byte, ceremony string) (string, error) {
+	tx, err := database.DB.Beginx()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	hash := onetime.Hash(encodeBase64URL(challenge))
+	var row struct {
+		Ceremony  string         `db:"ceremony"`
+		UserId    sql.NullString `db:"user_id"`
+		ExpiresAt time.Time      `db:"expires_at"`
+		UsedAt    sql.NullTime   `db:"used_at"`
+	}
+	err = tx.Get(&row, "SELECT ceremony, user_id, expires_at, used_at FROM webauthn_challenges WHERE challenge_hash = ? FOR UPDATE", hash)
+	if err == sql.ErrNoRows {
+		return "", ErrChallengeNotFound
+	} else if err != nil {
+		return "", err
+	}
+	if row.Ceremony != ceremony || row.UsedAt.Valid || time.Now().After(row.ExpiresAt) {
+		return "", ErrChallengeNotFound
+	}
+
+	if _, err = tx.Exec("UPDATE webauthn_challenges SET used_at = ? WHERE challenge_hash = ?", time.Now(), hash); err != nil {
+		return "", err
+	}
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return row.UserId.String, nil
+}
+
+// 사용자에게 등록된 패스키
+type StoredCredential struct {
+	Id         string     `db:"id" json:"id"`
+	Name       string     `db:"name" json:"name"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"lastUsedAt"`
+}
+
+func listCredentials(userId string) ([]StoredCredential, error) {
+	credentials := []StoredCredential{}
+	err := database.DB.Select(&credentials, "SELECT id, name, created_at, last_used_at FROM webauthn_credentials WHERE user_id = ? ORDER BY created_at", userId)
+	return credentials, err
+}
+
+func saveCredential(userId, name string, credential *Credential) error {
+	_, err := database.DB.Exec("INSERT INTO webauthn_credentials (id, user_id, public_key, sign_count, name, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		encodeBase64URL(credential.ID), userId, credential.PublicKey, credential.SignCount, name, time.Now())
+	return err
+}
+
+func findCredential(id string) (userId string, credential Credential, err error) {
+	var row struct {
+		UserId    string `db:"user_id"`
+		PublicKey []byte `db:"public_key"`
+		SignCount uint32 `db:"sign_count"`
+	}
+	err = database.DB.Get(&row, "SELECT user_id, public_key, sign_count FROM webauthn_credentials WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return "", Credential{}, ErrCredentialNotFound
+	} else if err != nil {
+		return "", Credential{}, err
+	}
+
+	rawId, err := decodeBase64URL(id)
+	if err != nil {
+		return "", Credential{}, err
+	}
+	return row.UserId, Credential{ID: rawId, PublicKey: row.PublicKey, SignCount: row.SignCount}, nil
+}
+
+func touchCredential(id string, signCount uint32) error {
+	_, err := database.DB.Exec("UPDATE webauthn_credentials SET sign_count = ?, last_used_at = ? WHERE id = ?", signCount, time.Now(), id)
+	return err
+}
+
+func deleteCredential(userId, id string) error {
+	result, err := database.DB.Exec("DELETE FROM webauthn_credentials WHERE id = ? AND user_id = ?", id, userId)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err == nil && affected == 0 {
+		return ErrCredentialNotFound
+	}
+	return err
+}
@@ -0,0 +1,246 @@
+// webauthn/verify.go
+package webauthn
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// authenticatorData 플래그
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+var (
+	ErrChallenge     = errors.New("챌린지가 일치하지 않습니다")
+	ErrOrigin        = errors.New("허용되지 않은 출처입니다")
+	ErrRPID          = errors.New("RP ID 가 일치하지 않습니다")
+	ErrUserFlags     = errors.New("사용자 확인이 필요합니다")
+	ErrCeremony      = errors.New("WebAuthn 요청 종류가 올바르지 않습니다")
+	ErrSignCount     = errors.New("인증기 서명 카운터가 올바르지 않습니다 (복제 의심)")
+	ErrMalformed     = errors.New("WebAuthn 응답 형식이 올바르지 않습니다")
+	ErrNoAttestedKey = errors.New("등록 응답에 공개 키가 없습니다")
+)
+
+// Relying Party 설정
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// 환경변수에서 설정 읽기
+//
+//	WEBAUTHN_RP_ID     RP ID (기본값 FRONTEND_URL 의 호스트)
+//	WEBAUTHN_RP_NAME   표시 이름 (기본값 거니월드)
+//	WEBAUTHN_ORIGINS   허용 출처, 쉼표 구분 (기본값 FRONTEND_URL)
+func LoadConfig() Config {
+	frontend := os.Getenv("FRONTEND_URL")
+
+	config := Config{RPID: os.Getenv("WEBAUTHN_RP_ID"), RPName: os.Getenv("WEBAUTHN_RP_NAME")}
+	if config.RPID == "" {
+		if parsed, err := url.Parse(frontend); err == nil {
+			config.RPID = parsed.Hostname()
+		}
+	}
+	if config.RPName == "" {
+		config.RPName = "거니월드"
+	}
+
+	origins := os.Getenv("WEBAUTHN_ORIGINS")
+	if origins == "" {
+		origins = frontend
+	}
+	for _, origin := range strings.Split(origins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			config.Origins = append(config.Origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return config
+}
+
+// 저장하는 인증기 자격 증명
+type Credential struct {
+	ID        []byte
+	PublicKey []byte // COSE_Key 원본
+	SignCount uint32
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// clientDataJSON 에 담긴 챌린지 (저장된 챌린지를 찾는 데 사용)
+func ChallengeFromClientData(clientDataJSON []byte) ([]byte, error) {
+	var data clientData
+	if err := json.Unmarshal(clientDataJSON, &data); err != nil {
+		return nil, ErrMalformed
+	}
+	return decodeBase64URL(data.Challenge)
+}
+
+// 등록(navigator.credentials.create) 응답 검증
+// attestation 은 "none" 으로 요청하므로 attStmt 는 검증하지 않고 인증기 공개 키만 받음
+func (c Config) VerifyRegistration(challenge, clientDataJSON, attestationObject []byte) (*Credential, error) {
+	if err := c.verifyClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return nil, err
+	}
+
+	value, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return nil, err
+	}
+	attestation, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, ErrMalformed
+	}
+	rawAuthData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return nil, ErrMalformed
+	}
+
+	authData, err := c.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+	if authData.flags&flagAttested == 0 || authData.publicKey == nil {
+		return nil, ErrNoAttestedKey
+	}
+	if _, err := parseCOSEKey(authData.publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{ID: authData.credentialID, PublicKey: authData.publicKey, SignCount: authData.signCount}, nil
+}
+
+// 로그인(navigator.credentials.get) 응답 검증 후 새 서명 카운터 반환
+func (c Config) VerifyAssertion(challenge []byte, credential Credential, clientDataJSON, rawAuthData, signature []byte) (uint32, error) {
+	if err := c.verifyClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := c.verifyAuthenticatorData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), rawAuthData...), clientDataHash[:]...)
+	if err := key.verify(signed, signature); err != nil {
+		return 0, err
+	}
+
+	// 카운터를 지원하는 인증기인데 값이 늘지 않았으면 복제된 인증기로 간주
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return 0, ErrSignCount
+	}
+	return authData.signCount, nil
+}
+
+func (c Config) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return ErrMalformed
+	}
+	if data.Type != ceremony {
+		return ErrCeremony
+	}
+
+	got, err := decodeBase64URL(data.Challenge)
+	if err != nil || len(challenge) == 0 || subtle.ConstantTimeCompare(got, challenge) != 1 {
+		return ErrChallenge
+	}
+
+	for _, origin := range c.Origins {
+		if data.Origin == origin {
+			return nil
+		}
+	}
+	return ErrOrigin
+}
+
+func (c Config) verifyAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	authData, err := parseAuthenticatorData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	rpIDHash := sha256.Sum256([]byte(c.RPID))
+	if !bytes.Equal(authData.rpIDHash, rpIDHash[:]) {
+		return nil, ErrRPID
+	}
+	// 비밀번호를 대신하므로 사용자 존재와 사용자 인증(PIN, 생체) 모두 필요
+	if authData.flags&flagUserPresent == 0 || authData.flags&flagUserVerified == 0 {
+		return nil, ErrUserFlags
+	}
+	return authData, nil
+}
+
+// rpIdHash(32) | flags(1) | signCount(4) | [aaguid(16) | credIdLen(2) | credId | COSE_Key] | [extensions]
+func parseAuthenticatorData(raw []byte) (*authenticatorData, error) {
+	if len(raw) < 37 {
+		return nil, ErrMalformed
+	}
+
+	authData := &authenticatorData{
+		rpIDHash:  raw[:32],
+		flags:     raw[32],
+		signCount: binary.BigEndian.Uint32(raw[33:37]),
+	}
+	if authData.flags&flagAttested == 0 {
+		return authData, nil
+	}
+
+	rest := raw[37:]
+	if len(rest) < 18 {
+		return nil, ErrMalformed
+	}
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLen == 0 || idLen > 1023 || len(rest) < idLen {
+		return nil, ErrMalformed
+	}
+	authData.credentialID = append([]byte(nil), rest[:idLen]...)
+	rest = rest[idLen:]
+
+	_, after, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, err
+	}
+	authData.publicKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+	return authData, nil
+}
+
+// 브라우저가 보내는 base64url (패딩 유무 모두 허용)
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
+
+func encodeBase64URL(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
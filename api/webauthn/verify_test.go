@@ -0,0 +1,445 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const (
+	testRPID   = "game.example.com"
+	testOrigin = "https://game.example.com"
+)
+
+var testConfig = Config{RPID: testRPID, RPName: "test", Origins: []string{testOrigin}}
+
+// 순서가 고정된 CBOR 맵 (테스트 인코더용)
+type cborMap []cborPair
+
+type cborPair struct {
+	key   interface{}
+	value interface{}
+}
+
+func cborHeader(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg <= 0xffff:
+		buf := []byte{major<<5 | 25, 0, 0}
+		binary.BigEndian.PutUint16(buf[1:], uint16(arg))
+		return buf
+	case arg <= 0xffffffff:
+		buf := []byte{major<<5 | 26, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(buf[1:], uint32(arg))
+		return buf
+	}
+	buf := []byte{major<<5 | 27, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint64(buf[1:], arg)
+	return buf
+}
+
+func cborEncode(value interface{}) []byte {
+	switch v := value.(type) {
+	case int:
+		if v >= 0 {
+			return cborHeader(0, uint64(v))
+		}
+		return cborHeader(1, uint64(-1-v))
+	case []byte:
+		return append(cborHeader(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHeader(3, uint64(len(v))), v...)
+	case []interface{}:
+		out := cborHeader(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, cborEncode(item)...)
+		}
+		return out
+	case cborMap:
+		out := cborHeader(5, uint64(len(v)))
+		for _, pair := range v {
+			out = append(out, cborEncode(pair.key)...)
+			out = append(out, cborEncode(pair.value)...)
+		}
+		return out
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	}
+	panic("unsupported test value")
+}
+
+// 메모리 안의 ES256 키로 동작하는 소프트웨어 인증기
+type softAuthenticator struct {
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	rpID         string
+	flags        byte
+	signCount    uint32
+}
+
+func newAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{
+		key:          key,
+		credentialID: []byte("credential-0001"),
+		rpID:         testRPID,
+		flags:        flagUserPresent | flagUserVerified,
+	}
+}
+
+func (a *softAuthenticator) coseKey() []byte {
+	x := make([]byte, 32)
+	y := make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	return cborEncode(cborMap{
+		{1, 2},
+		{3, algES256},
+		{-1, 1},
+		{-2, x},
+		{-3, y},
+	})
+}
+
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags := a.flags
+	if attested {
+		flags |= flagAttested
+	}
+	out := append([]byte(nil), rpIDHash[:]...)
+	out = append(out, flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(out[33:], a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...) // aaguid
+		out = append(out, byte(len(a.credentialID)>>8), byte(len(a.credentialID)))
+		out = append(out, a.credentialID...)
+		out = append(out, a.coseKey()...)
+	}
+	return out
+}
+
+func clientDataJSON(t *testing.T, ceremony string, challenge []byte, origin string) []byte {
+	t.Helper()
+	data, err := json.Marshal(clientData{Type: ceremony, Challenge: encodeBase64URL(challenge), Origin: origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (a *softAuthenticator) register(t *testing.T, challenge []byte, origin string) (rawClientData, attestationObject []byte) {
+	t.Helper()
+	attestationObject = cborEncode(cborMap{
+		{"fmt", "none"},
+		{"attStmt", cborMap{}},
+		{"authData", a.authData(true)},
+	})
+	return clientDataJSON(t, "webauthn.create", challenge, origin), attestationObject
+}
+
+func (a *softAuthenticator) assert(t *testing.T, challenge []byte, origin string) (rawClientData, authData, signature []byte) {
+	t.Helper()
+	rawClientData = clientDataJSON(t, "webauthn.get", challenge, origin)
+	authData = a.authData(false)
+	clientDataHash := sha256.Sum256(rawClientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawClientData, authData, signature
+}
+
+func registered(t *testing.T, a *softAuthenticator) Credential {
+	t.Helper()
+	challenge := []byte("registration-challenge")
+	clientData, attestation := a.register(t, challenge, testOrigin)
+	credential, err := testConfig.VerifyRegistration(challenge, clientData, attestation)
+	if err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+	return *credential
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	a := newAuthenticator(t)
+	credential := registered(t, a)
+	if !bytes.Equal(credential.ID, a.credentialID) || !bytes.Equal(credential.PublicKey, a.coseKey()) {
+		t.Fatalf("unexpected credential %+v", credential)
+	}
+
+	challenge := []byte("login-challenge")
+	for i := 0; i < 2; i++ {
+		a.signCount++
+		clientData, authData, signature := a.assert(t, challenge, testOrigin)
+		signCount, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature)
+		if err != nil {
+			t.Fatalf("assertion %d failed: %v", i, err)
+		}
+		if signCount != a.signCount {
+			t.Fatalf("sign count %d, want %d", signCount, a.signCount)
+		}
+		credential.SignCount = signCount
+	}
+}
+
+func TestRegistrationRejected(t *testing.T) {
+	challenge := []byte("registration-challenge")
+
+	tests := []struct {
+		name   string
+		modify func(a *softAuthenticator) (clientData, attestation, challenge []byte)
+		want   error
+	}{
+		{"wrong origin", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			clientData, attestation := a.register(t, challenge, "https://evil.example.com")
+			return clientData, attestation, challenge
+		}, ErrOrigin},
+		{"wrong rpIdHash", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			a.rpID = "evil.example.com"
+			clientData, attestation := a.register(t, challenge, testOrigin)
+			return clientData, attestation, challenge
+		}, ErrRPID},
+		{"wrong challenge", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			clientData, attestation := a.register(t, []byte("other"), testOrigin)
+			return clientData, attestation, challenge
+		}, ErrChallenge},
+		{"login ceremony", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			_, attestation := a.register(t, challenge, testOrigin)
+			return clientDataJSON(t, "webauthn.get", challenge, testOrigin), attestation, challenge
+		}, ErrCeremony},
+		{"missing UP", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			a.flags = flagUserVerified
+			clientData, attestation := a.register(t, challenge, testOrigin)
+			return clientData, attestation, challenge
+		}, ErrUserFlags},
+		{"missing UV", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			a.flags = flagUserPresent
+			clientData, attestation := a.register(t, challenge, testOrigin)
+			return clientData, attestation, challenge
+		}, ErrUserFlags},
+		{"no attested key", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			attestation := cborEncode(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}, {"authData", a.authData(false)}})
+			return clientDataJSON(t, "webauthn.create", challenge, testOrigin), attestation, challenge
+		}, ErrNoAttestedKey},
+		{"missing authData", func(a *softAuthenticator) ([]byte, []byte, []byte) {
+			attestation := cborEncode(cborMap{{"fmt", "none"}, {"attStmt", cborMap{}}})
+			return clientDataJSON(t, "webauthn.create", challenge, testOrigin), attestation, challenge
+		}, ErrMalformed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clientData, attestation, expected := test.modify(newAuthenticator(t))
+			_, err := testConfig.VerifyRegistration(expected, clientData, attestation)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestAssertionRejected(t *testing.T) {
+	challenge := []byte("login-challenge")
+
+	t.Run("wrong origin", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		a.signCount = 1
+		clientData, authData, signature := a.assert(t, challenge, "https://evil.example.com")
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); !errors.Is(err, ErrOrigin) {
+			t.Fatalf("got %v, want ErrOrigin", err)
+		}
+	})
+
+	t.Run("wrong rpIdHash", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		a.rpID = "evil.example.com"
+		a.signCount = 1
+		clientData, authData, signature := a.assert(t, challenge, testOrigin)
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); !errors.Is(err, ErrRPID) {
+			t.Fatalf("got %v, want ErrRPID", err)
+		}
+	})
+
+	t.Run("missing UP", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		a.flags = flagUserVerified
+		a.signCount = 1
+		clientData, authData, signature := a.assert(t, challenge, testOrigin)
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); !errors.Is(err, ErrUserFlags) {
+			t.Fatalf("got %v, want ErrUserFlags", err)
+		}
+	})
+
+	t.Run("counter goes backwards", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		credential.SignCount = 10
+		for _, count := range []uint32{9, 10} {
+			a.signCount = count
+			clientData, authData, signature := a.assert(t, challenge, testOrigin)
+			if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); !errors.Is(err, ErrSignCount) {
+				t.Fatalf("count %d: got %v, want ErrSignCount", count, err)
+			}
+		}
+	})
+
+	t.Run("counter not supported", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		clientData, authData, signature := a.assert(t, challenge, testOrigin)
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); err != nil {
+			t.Fatalf("authenticator without counter rejected: %v", err)
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		a.signCount = 1
+		clientData, authData, signature := a.assert(t, challenge, testOrigin)
+
+		// 다른 키로 서명
+		other := newAuthenticator(t)
+		other.signCount = 1
+		_, _, otherSignature := other.assert(t, challenge, testOrigin)
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, otherSignature); !errors.Is(err, errBadSignature) {
+			t.Fatalf("signature from another key: got %v", err)
+		}
+
+		// 서명 후 authenticatorData 변조 (카운터 증가)
+		tampered := append([]byte(nil), authData...)
+		tampered[36]++
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, tampered, signature); !errors.Is(err, errBadSignature) {
+			t.Fatalf("tampered authData: got %v", err)
+		}
+
+		// 서명 바이트 변조
+		broken := append([]byte(nil), signature...)
+		broken[len(broken)-1] ^= 0x01
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, broken); !errors.Is(err, errBadSignature) {
+			t.Fatalf("modified signature: got %v", err)
+		}
+	})
+
+	t.Run("registration response", func(t *testing.T) {
+		a := newAuthenticator(t)
+		credential := registered(t, a)
+		clientData := clientDataJSON(t, "webauthn.create", challenge, testOrigin)
+		_, authData, signature := a.assert(t, challenge, testOrigin)
+		if _, err := testConfig.VerifyAssertion(challenge, credential, clientData, authData, signature); !errors.Is(err, ErrCeremony) {
+			t.Fatalf("got %v, want ErrCeremony", err)
+		}
+	})
+}
+
+func TestTruncatedInputs(t *testing.T) {
+	a := newAuthenticator(t)
+	challenge := []byte("registration-challenge")
+	clientData, attestation := a.register(t, challenge, testOrigin)
+
+	// 어느 위치에서 잘려도 패닉 없이 거부
+	for i := 0; i < len(attestation); i++ {
+		if _, err := testConfig.VerifyRegistration(challenge, clientData, attestation[:i]); err == nil {
+			t.Fatalf("attestation truncated to %d bytes accepted", i)
+		}
+	}
+
+	authData := a.authData(true)
+	for i := 0; i < len(authData); i++ {
+		if parsed, err := parseAuthenticatorData(authData[:i]); err == nil {
+			t.Fatalf("authData truncated to %d bytes accepted: %+v", i, parsed)
+		}
+	}
+}
+
+func TestDecodeCBORRejectsMalformed(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":                     {},
+		"byte string past end":      {0x45, 0x01, 0x02},
+		"oversized byte string":     append([]byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, 0x00),
+		"oversized text string":     {0x7a, 0xff, 0xff, 0xff, 0xff, 'a'},
+		"oversized array":           {0x9b, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"oversized map":             {0xba, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01},
+		"truncated length argument": {0x59, 0x01},
+		"integer overflow":          {0x1b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"indefinite byte string":    {0x5f, 0x41, 0x00, 0xff},
+		"indefinite array":          {0x9f, 0x01, 0xff},
+		"reserved argument":         {0x1c},
+		"byte string map key":       {0xa1, 0x41, 0x00, 0x01},
+		"tag":                       {0xc0, 0x01},
+		"too deep":                  append(bytes.Repeat([]byte{0x81}, 20), 0x01),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if value, _, err := decodeCBOR(data); err == nil {
+				t.Fatalf("decoded %x as %v", data, value)
+			}
+		})
+	}
+}
+
+func TestDecodeCBOR(t *testing.T) {
+	data := cborEncode(cborMap{
+		{1, 2},
+		{-7, "text"},
+		{"bytes", []byte{1, 2, 3}},
+		{"list", []interface{}{1, true, false}},
+	})
+	value, rest, err := decodeCBOR(append(data, 0xaa))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, []byte{0xaa}) {
+		t.Fatalf("rest = %x", rest)
+	}
+	m := value.(map[interface{}]interface{})
+	if m[int64(1)] != int64(2) || m[int64(-7)] != "text" || !bytes.Equal(m["bytes"].([]byte), []byte{1, 2, 3}) {
+		t.Fatalf("unexpected map %v", m)
+	}
+	if list := m["list"].([]interface{}); len(list) != 3 || list[0] != int64(1) || list[1] != true || list[2] != false {
+		t.Fatalf("unexpected list %v", list)
+	}
+}
+
+func TestParseCOSEKeyRejectsInvalid(t *testing.T) {
+	a := newAuthenticator(t)
+	x := make([]byte, 32)
+	a.key.X.FillBytes(x)
+
+	tests := map[string][]byte{
+		"off curve":         cborEncode(cborMap{{1, 2}, {3, algES256}, {-1, 1}, {-2, x}, {-3, x}}),
+		"wrong curve":       cborEncode(cborMap{{1, 2}, {3, algES256}, {-1, 2}, {-2, x}, {-3, x}}),
+		"short coordinate":  cborEncode(cborMap{{1, 2}, {3, algES256}, {-1, 1}, {-2, x[:31]}, {-3, x}}),
+		"unknown algorithm": cborEncode(cborMap{{1, 2}, {3, -35}, {-1, 1}, {-2, x}, {-3, x}}),
+		"not a map":         cborEncode([]interface{}{1, 2}),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseCOSEKey(data); err == nil {
+				t.Fatal("invalid key accepted")
+			}
+		})
+	}
+}
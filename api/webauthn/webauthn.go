@@ -0,0 +1,233 @@
+// webauthn/webauthn.go
+package webauthn
+
+import (
+	"guny-world-backend/api/auth"
+	"guny-world-backend/api/database"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// 클라이언트 대기 시간 (밀리초)
+const timeoutMillis = 300000
+
+var pubKeyCredParams = []fiber.Map{
+	{"type": "public-key", "alg": algES256},
+	{"type": "public-key", "alg": algEdDSA},
+	{"type": "public-key", "alg": algRS256},
+}
+
+// 브라우저가 PublicKeyCredential.toJSON() 으로 보내는 등록 응답
+type AttestationResponse struct {
+	Id       string `json:"id"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AttestationObject string `json:"attestationObject"`
+	} `json:"response"`
+}
+
+// 브라우저가 PublicKeyCredential.toJSON() 으로 보내는 로그인 응답
+type AssertionResponse struct {
+	Id       string `json:"id"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+// 패스키 등록 시작 핸들러 (auth.RequireAuth 뒤에서 동작)
+// navigator.credentials.create() 에 넘길 옵션 반환
+func RegisterBegin(c *fiber.Ctx) (err error) {
+	userId := auth.GetPrincipal(c).UserId
+	config := LoadConfig()
+
+	challenge, err := newChallenge(ceremonyRegister, userId)
+	if err != nil {
+		log.Println("챌린지 생성 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	existing, err := listCredentials(userId)
+	if err != nil {
+		log.Println("패스키 목록 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	exclude := make([]fiber.Map, 0, len(existing))
+	for _, credential := range existing {
+		exclude = append(exclude, fiber.Map{"type": "public-key", "id": credential.Id})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"rp":               fiber.Map{"id": config.RPID, "name": config.RPName},
+		"user":             fiber.Map{"id": encodeBase64URL([]byte(userId)), "name": userId, "displayName": displayName(userId)},
+		"challenge":        encodeBase64URL(challenge),
+		"pubKeyCredParams": pubKeyCredParams,
+		"timeout":          timeoutMillis,
+		"attestation":      "none",
+		// 로그인은 사용자를 묻지 않으므로 기기에 저장되는(discoverable) 패스키만 등록
+		"authenticatorSelection": fiber.Map{
+			"residentKey":        "required",
+			"requireResidentKey": true,
+			"userVerification":   "required",
+		},
+		"excludeCredentials": exclude,
+	})
+}
+
+// 패스키 등록 완료 핸들러 (auth.RequireAuth 뒤에서 동작)
+func RegisterFinish(c *fiber.Ctx) (err error) {
+	userId := auth.GetPrincipal(c).UserId
+
+	type RequestQuery struct {
+		Name       string              `json:"name"`
+		Credential AttestationResponse `json:"credential"`
+	}
+
+	// Body 파싱
+	var requestQuery RequestQuery
+	if err := c.BodyParser(&requestQuery); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "요청 데이터를 파싱하는 데 실패했습니다."})
+	}
+	name := strings.TrimSpace(requestQuery.Name)
+	if utf8.RuneCountInString(name) > 64 {
+		return c.Status(400).JSON(fiber.Map{"error": "패스키 이름은 64자 이내여야 합니다."})
+	}
+
+	clientDataJSON, err1 := decodeBase64URL(requestQuery.Credential.Response.ClientDataJSON)
+	attestationObject, err2 := decodeBase64URL(requestQuery.Credential.Response.AttestationObject)
+	if err1 != nil || err2 != nil {
+		return c.Status(400).JSON(fiber.Map{"error": ErrMalformed.Error()})
+	}
+
+	// 이 사용자에게 발급한 챌린지인지 확인
+	challenge, err := ChallengeFromClientData(clientDataJSON)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": ErrMalformed.Error()})
+	}
+	owner, err := consumeChallenge(challenge, ceremonyRegister)
+	if err == ErrChallengeNotFound || (err == nil && owner != userId) {
+		return c.Status(400).JSON(fiber.Map{"error": ErrChallengeNotFound.Error()})
+	} else if err != nil {
+		log.Println("챌린지 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	credential, err := LoadConfig().VerifyRegistration(challenge, clientDataJSON, attestationObject)
+	if err != nil {
+		log.Println("패스키 등록 검증 실패: ", err)
+		return c.Status(400).JSON(fiber.Map{"error": "패스키를 확인하지 못했습니다."})
+	}
+
+	if err := saveCredential(userId, name, credential); err != nil {
+		if strings.Contains(err.Error(), "Duplicate entry") {
+			return c.Status(409).JSON(fiber.Map{"error": "이미 등록된 패스키입니다."})
+		}
+		log.Println("패스키 저장 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+
+	return c.Status(200).JSON(fiber.Map{"message": "패스키가 등록되었습니다.", "id": encodeBase64URL(credential.ID)})
+}
+
+// 등록된 패스키 목록 핸들러 (auth.RequireAuth 뒤에서 동작)
+func ListCredentials(c *fiber.Ctx) (err error) {
+	credentials, err := listCredentials(auth.GetPrincipal(c).UserId)
+	if err != nil {
+		log.Println("패스키 목록 조회 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	return c.Status(200).JSON(fiber.Map{"credentials": credentials})
+}
+
+// 패스키 삭제 핸들러 (auth.RequireAuth 뒤에서 동작)
+func DeleteCredential(c *fiber.Ctx) (err error) {
+	err = deleteCredential(auth.GetPrincipal(c).UserId, c.Params("id"))
+	if err == ErrCredentialNotFound {
+		return c.Status(404).JSON(fiber.Map{"error": err.Error()})
+	} else if err != nil {
+		log.Println("패스키 삭제 실패: ", err)
+		return c.Status(500).JSON(fiber.Map{"error": "서버 내부 오류입니다."})
+	}
+	return c.Status(200).JSON(fiber.Map{"message": "패스키가 삭제되었습니다."})
+}
+
+// 패스키 로그인 시작 (navigator.credentials.get() 옵션)
+// 계정 존재 여부가 드러나지 않도록 allowCredentials 는 비우고 인증기에 저장된 패스키(discoverable)로 로그인
+func BeginLogin() (fiber.Map, error) {
+	config := LoadConfig()
+
+	challenge, err := newChallenge(ceremonyLogin, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return fiber.Map{
+		"challenge":        encodeBase64URL(challenge),
+		"rpId":             config.RPID,
+		"timeout":          timeoutMillis,
+		"userVerification": "required",
+		"allowCredentials": []fiber.Map{},
+	}, nil
+}
+
+// 패스키 로그인 응답을 검증하고 사용자 ID 반환
+func FinishLogin(response AssertionResponse) (string, error) {
+	clientDataJSON, err1 := decodeBase64URL(response.Response.ClientDataJSON)
+	authenticatorData, err2 := decodeBase64URL(response.Response.AuthenticatorData)
+	signature, err3 := decodeBase64URL(response.Response.Signature)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return "", ErrMalformed
+	}
+
+	userId, credential, err := findCredential(response.Id)
+	if err != nil {
+		return "", err
+	}
+
+	// userHandle 이 오면 등록 때 넣은 사용자 ID 와 같아야 함
+	if response.Response.UserHandle != "" {
+		handle, err := decodeBase64URL(response.Response.UserHandle)
+		if err != nil || string(handle) != userId {
+			return "", ErrCredentialNotFound
+		}
+	}
+
+	challenge, err := ChallengeFromClientData(clientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	owner, err := consumeChallenge(challenge, ceremonyLogin)
+	if err != nil {
+		return "", err
+	}
+	if owner != "" && owner != userId {
+		return "", ErrChallengeNotFound
+	}
+
+	signCount, err := LoadConfig().VerifyAssertion(challenge, credential, clientDataJSON, authenticatorData, signature)
+	if err != nil {
+		return "", err
+	}
+
+	if err := touchCredential(response.Id, signCount); err != nil {
+		return "", err
+	}
+	return userId, nil
+}
+
+// 인증기에 보여줄 이름 (닉네임이 없으면 사용자 ID)
+func displayName(userId string) string {
+	db := database.DB
+
+	var nickname string
+	err := db.Get(&nickname, "SELECT nickname FROM accounts WHERE id = ?", userId)
+	if err != nil || nickname == "" {
+		return userId
+	}
+	return nickname
+}
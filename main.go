@@ -2,7 +2,12 @@ package main
 
 import (
 	"guny-world-backend/api"
+	"guny-world-backend/api/account"
+	"guny-world-backend/api/cleanup"
 	"guny-world-backend/api/database"
+	"guny-world-backend/api/export"
+	"guny-world-backend/api/mail"
+	"guny-world-backend/api/token"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,6 +22,11 @@ func main() {
 	}
 	
 	database.InitDB()
+	token.Init()
+	mail.Init()
+	account.StartPurgeJob()
+	export.StartCleanupJob()
+	cleanup.StartJob()
 	app := fiber.New()
 	app.Use(recover.New())
 	app.Use(cors.New())
@@ -0,0 +1,12 @@
+-- 리프레시 토큰 저장소 (원문 대신 SHA-256 해시만 저장)
+CREATE TABLE refresh_tokens (
+    token_hash CHAR(64)     NOT NULL PRIMARY KEY,
+    family_id  CHAR(36)     NOT NULL,
+    user_id    VARCHAR(255) NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    used_at    DATETIME     NULL,
+    revoked_at DATETIME     NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_refresh_tokens_family (family_id),
+    INDEX idx_refresh_tokens_user (user_id)
+);
@@ -0,0 +1,14 @@
+-- 로그아웃으로 폐기된 엑세스 토큰 (만료 시각까지만 의미 있음)
+CREATE TABLE revoked_access_tokens (
+    jti        CHAR(36)     NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(255) NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_revoked_access_tokens_expires (expires_at)
+);
+
+-- "모든 기기에서 로그아웃" 시점 이전에 발급된 엑세스 토큰은 거부
+CREATE TABLE access_token_cutoffs (
+    user_id        VARCHAR(255) NOT NULL PRIMARY KEY,
+    revoked_before DATETIME     NOT NULL
+);
@@ -0,0 +1,12 @@
+-- 로그인 세션 (리프레시 토큰 패밀리 하나당 하나)
+CREATE TABLE sessions (
+    id           CHAR(36)     NOT NULL PRIMARY KEY,
+    user_id      VARCHAR(255) NOT NULL,
+    login_method VARCHAR(20)  NOT NULL,
+    user_agent   VARCHAR(512) NOT NULL DEFAULT '',
+    ip           VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at   DATETIME     NOT NULL,
+    last_used_at DATETIME     NOT NULL,
+    revoked_at   DATETIME     NULL,
+    INDEX idx_sessions_user (user_id)
+);
@@ -0,0 +1,14 @@
+-- 이메일 인증 여부 (기존 계정은 인증된 것으로 처리)
+ALTER TABLE users ADD COLUMN email_verified_at DATETIME NULL;
+UPDATE users SET email_verified_at = NOW();
+
+-- 이메일 인증 토큰 (원문 대신 SHA-256 해시만 저장)
+CREATE TABLE email_verifications (
+    token_hash CHAR(64)     NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(255) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    used_at    DATETIME     NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_email_verifications_user (user_id, created_at)
+);
@@ -0,0 +1,9 @@
+-- 비밀번호 재설정 토큰 (원문 대신 SHA-256 해시만 저장)
+CREATE TABLE password_resets (
+    token_hash CHAR(64)     NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(255) NOT NULL,
+    expires_at DATETIME     NOT NULL,
+    used_at    DATETIME     NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_password_resets_user (user_id, created_at)
+);
@@ -0,0 +1,8 @@
+-- 로그인 실패 기록 (LOGIN_THROTTLE_STORE=mysql 일 때 여러 서버가 공유)
+CREATE TABLE login_failures (
+    throttle_key    VARCHAR(320) NOT NULL PRIMARY KEY,
+    failures        INT          NOT NULL,
+    last_failure_at DATETIME     NOT NULL,
+    expires_at      DATETIME     NOT NULL,
+    INDEX idx_login_failures_expires (expires_at)
+);
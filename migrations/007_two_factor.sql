@@ -0,0 +1,18 @@
+-- TOTP 2단계 인증 (secret 은 DATA_ENCRYPTION_KEY 로 암호화)
+CREATE TABLE user_totp (
+    user_id        VARCHAR(255) NOT NULL PRIMARY KEY,
+    secret         VARCHAR(255) NOT NULL,
+    confirmed_at   DATETIME     NULL,
+    last_used_step BIGINT       NOT NULL DEFAULT 0,
+    created_at     DATETIME     NOT NULL
+);
+
+-- 복구 코드 (SHA-256 해시만 저장, 1회용)
+CREATE TABLE totp_recovery_codes (
+    id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id    VARCHAR(255) NOT NULL,
+    code_hash  CHAR(64)     NOT NULL,
+    used_at    DATETIME     NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_totp_recovery_codes_user (user_id)
+);
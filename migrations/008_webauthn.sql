@@ -0,0 +1,21 @@
+-- 패스키(WebAuthn) 자격 증명
+CREATE TABLE webauthn_credentials (
+    id           VARCHAR(1400) CHARACTER SET ascii COLLATE ascii_bin NOT NULL PRIMARY KEY,
+    user_id      VARCHAR(255)  NOT NULL,
+    public_key   BLOB          NOT NULL,
+    sign_count   INT UNSIGNED  NOT NULL DEFAULT 0,
+    name         VARCHAR(64)   NOT NULL DEFAULT '',
+    created_at   DATETIME      NOT NULL,
+    last_used_at DATETIME      NULL,
+    INDEX idx_webauthn_credentials_user (user_id)
+);
+
+-- 등록/로그인 챌린지 (1회용, 5분)
+CREATE TABLE webauthn_challenges (
+    challenge_hash CHAR(64)     NOT NULL PRIMARY KEY,
+    ceremony       VARCHAR(16)  NOT NULL,
+    user_id        VARCHAR(255) NULL,
+    expires_at     DATETIME     NOT NULL,
+    used_at        DATETIME     NULL,
+    created_at     DATETIME     NOT NULL
+);
@@ -0,0 +1,46 @@
+-- users / naver_user_info 를 accounts + identities 로 통합
+-- JWT 의 user_id 는 항상 accounts.id
+
+-- 1. users 를 accounts 로 변경 (비밀번호 계정의 id 는 그대로 유지)
+RENAME TABLE users TO accounts;
+ALTER TABLE accounts
+    CHANGE COLUMN user_id email VARCHAR(255) NULL,
+    MODIFY COLUMN password VARCHAR(255) NULL,
+    ADD COLUMN profile_image VARCHAR(1024) NULL,
+    ADD COLUMN name VARCHAR(255) NULL,
+    ADD COLUMN created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN updated_at DATETIME NULL,
+    ADD COLUMN legacy_naver_user_id VARCHAR(255) NULL,
+    ADD UNIQUE INDEX idx_accounts_email (email);
+
+-- 2. 외부 로그인 연결
+CREATE TABLE identities (
+    id               BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    account_id       BIGINT       NOT NULL,
+    provider         VARCHAR(20)  NOT NULL,
+    provider_subject VARCHAR(255) NOT NULL,
+    email            VARCHAR(255) NULL,
+    created_at       DATETIME     NOT NULL,
+    updated_at       DATETIME     NULL,
+    UNIQUE INDEX idx_identities_provider_subject (provider, provider_subject),
+    INDEX idx_identities_account (account_id)
+);
+
+-- 3. 네이버 사용자마다 새 계정 생성 (이메일이 같아도 비밀번호 계정과 자동 연결하지 않음)
+INSERT INTO accounts (email, password, nickname, profile_image, name, created_at, updated_at, email_verified_at, legacy_naver_user_id)
+SELECT NULL, NULL, nickname, profile_image, name, created_at, updated_at, NULL, user_id FROM naver_user_info;
+
+INSERT INTO identities (account_id, provider, provider_subject, email, created_at)
+SELECT id, 'naver', legacy_naver_user_id, legacy_naver_user_id, created_at FROM accounts WHERE legacy_naver_user_id IS NOT NULL;
+
+-- 4. 네이버 이메일로 저장되어 있던 사용자 ID 를 계정 ID 로 변경
+UPDATE refresh_tokens r JOIN accounts a ON a.legacy_naver_user_id = r.user_id SET r.user_id = a.id;
+UPDATE sessions s JOIN accounts a ON a.legacy_naver_user_id = s.user_id SET s.user_id = a.id;
+UPDATE revoked_access_tokens t JOIN accounts a ON a.legacy_naver_user_id = t.user_id SET t.user_id = a.id;
+UPDATE access_token_cutoffs t JOIN accounts a ON a.legacy_naver_user_id = t.user_id SET t.user_id = a.id;
+UPDATE webauthn_credentials w JOIN accounts a ON a.legacy_naver_user_id = w.user_id SET w.user_id = a.id;
+
+ALTER TABLE accounts DROP COLUMN legacy_naver_user_id;
+
+-- 5. 이전 테이블은 확인 후 삭제
+RENAME TABLE naver_user_info TO naver_user_info_legacy;
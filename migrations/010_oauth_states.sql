@@ -0,0 +1,11 @@
+-- 외부 로그인 state / PKCE code_verifier (1회용, 10분)
+CREATE TABLE oauth_states (
+    state_hash    CHAR(64)     NOT NULL PRIMARY KEY,
+    provider      VARCHAR(20)  NOT NULL,
+    binding_hash  CHAR(64)     NOT NULL,
+    account_id    VARCHAR(255) NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at    DATETIME     NOT NULL,
+    used_at       DATETIME     NULL,
+    created_at    DATETIME     NOT NULL
+);
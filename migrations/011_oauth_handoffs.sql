@@ -0,0 +1,9 @@
+-- 외부 로그인 콜백 후 프론트엔드가 토큰으로 교환하는 1회용 코드 (1분)
+CREATE TABLE oauth_handoffs (
+    code_hash    CHAR(64)     NOT NULL PRIMARY KEY,
+    account_id   VARCHAR(255) NOT NULL,
+    login_method VARCHAR(20)  NOT NULL,
+    expires_at   DATETIME     NOT NULL,
+    used_at      DATETIME     NULL,
+    created_at   DATETIME     NOT NULL
+);
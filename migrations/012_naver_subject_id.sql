@@ -0,0 +1,3 @@
+-- 네이버 연결 식별자를 이메일에서 네이버 회원 id 로 변경
+-- id 는 네이버 API 로만 알 수 있으므로, 기존 연결은 "email:" 을 붙여 두고 다음 로그인 때 id 로 바꿈
+UPDATE identities SET provider_subject = CONCAT('email:', provider_subject) WHERE provider = 'naver';
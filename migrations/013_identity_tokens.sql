@@ -0,0 +1,5 @@
+-- 외부 로그인 제공자 토큰 (DATA_ENCRYPTION_KEY 로 암호화, 연결 해제/탈퇴 시 제공자에 폐기 요청)
+ALTER TABLE identities
+    ADD COLUMN access_token     TEXT     NULL,
+    ADD COLUMN refresh_token    TEXT     NULL,
+    ADD COLUMN token_expires_at DATETIME NULL;
@@ -0,0 +1,8 @@
+-- 계정별 역할 (엑세스 토큰의 roles 클레임으로 발급)
+-- 첫 운영자는 직접 추가: INSERT INTO account_roles (account_id, role, created_at) VALUES (<계정 id>, 'admin', NOW());
+CREATE TABLE account_roles (
+    account_id BIGINT      NOT NULL,
+    role       VARCHAR(32) NOT NULL,
+    created_at DATETIME    NOT NULL,
+    PRIMARY KEY (account_id, role)
+);
@@ -0,0 +1,16 @@
+-- 계정 정지 상태
+ALTER TABLE accounts
+    ADD COLUMN suspended_at      DATETIME     NULL,
+    ADD COLUMN suspension_reason VARCHAR(255) NULL;
+
+-- 관리자 작업 기록
+CREATE TABLE admin_audit_logs (
+    id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    actor_id   VARCHAR(255) NOT NULL,
+    action     VARCHAR(64)  NOT NULL,
+    target_id  VARCHAR(255) NOT NULL,
+    detail     JSON         NOT NULL,
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    created_at DATETIME     NOT NULL,
+    INDEX idx_admin_audit_logs_target (target_id, id)
+);
@@ -0,0 +1,2 @@
+-- 계정 정지 종료 시각 (NULL 이면 영구 정지)
+ALTER TABLE accounts ADD COLUMN suspended_until DATETIME NULL;
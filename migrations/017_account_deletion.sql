@@ -0,0 +1,5 @@
+-- 회원 탈퇴 (deleted_at 이후 유예 기간이 지나면 개인 정보를 지우고 purged_at 기록)
+ALTER TABLE accounts
+    ADD COLUMN deleted_at DATETIME NULL,
+    ADD COLUMN purged_at  DATETIME NULL,
+    ADD INDEX idx_accounts_deleted (deleted_at);
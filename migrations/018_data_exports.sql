@@ -0,0 +1,13 @@
+-- 개인정보 열람(내보내기) 요청
+CREATE TABLE data_exports (
+    id                  CHAR(36)     NOT NULL PRIMARY KEY,
+    account_id          VARCHAR(255) NOT NULL,
+    status              VARCHAR(16)  NOT NULL,
+    file_path           VARCHAR(512) NULL,
+    download_token_hash CHAR(64)     NULL,
+    download_expires_at DATETIME     NULL,
+    expires_at          DATETIME     NULL,
+    created_at          DATETIME     NOT NULL,
+    completed_at        DATETIME     NULL,
+    INDEX idx_data_exports_account (account_id, created_at)
+);
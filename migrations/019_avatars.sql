@@ -0,0 +1,3 @@
+-- 직접 올린 프로필 이미지 저장 경로 (썸네일은 <avatar_key>/<크기>.jpg)
+ALTER TABLE accounts
+    ADD COLUMN avatar_key VARCHAR(255) NULL AFTER profile_image;
@@ -0,0 +1,12 @@
+-- 전체 로그아웃 기준 시각을 토큰 iat 와 같은 단위(Unix 초)로 저장
+-- DATETIME 은 반올림될 수 있어 같은 초에 발급된 토큰이 살아남거나 새 로그인이 거부될 수 있었음
+-- 기존 값은 UTC 로 저장되어 있음
+SET time_zone = '+00:00';
+
+ALTER TABLE access_token_cutoffs
+    ADD COLUMN revoked_through BIGINT NOT NULL DEFAULT 0;
+
+UPDATE access_token_cutoffs SET revoked_through = UNIX_TIMESTAMP(revoked_before);
+
+ALTER TABLE access_token_cutoffs
+    DROP COLUMN revoked_before;
@@ -0,0 +1,5 @@
+-- 만료 기록 정리 작업(cleanup)용 인덱스
+CREATE INDEX idx_webauthn_challenges_expires ON webauthn_challenges (expires_at);
+CREATE INDEX idx_oauth_states_expires ON oauth_states (expires_at);
+CREATE INDEX idx_oauth_handoffs_expires ON oauth_handoffs (expires_at);
+CREATE INDEX idx_refresh_tokens_expires ON refresh_tokens (expires_at);
@@ -0,0 +1,11 @@
+-- 계정 연결을 브라우저 이동으로 시작하기 위한 1회용 티켓 (1분)
+-- 로그인한 SPA 가 발급받아 주소로 이동하면, 그 요청에서 연결 확인 쿠키를 심고 제공자로 이동
+CREATE TABLE oauth_link_tickets (
+    ticket_hash CHAR(64)     NOT NULL PRIMARY KEY,
+    provider    VARCHAR(20)  NOT NULL,
+    account_id  VARCHAR(255) NOT NULL,
+    expires_at  DATETIME     NOT NULL,
+    used_at     DATETIME     NULL,
+    created_at  DATETIME     NOT NULL,
+    INDEX idx_oauth_link_tickets_expires (expires_at)
+);
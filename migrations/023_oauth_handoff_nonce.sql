@@ -0,0 +1,4 @@
+-- 외부 로그인을 시작한 프론트엔드만 1회용 코드를 교환할 수 있도록 nonce 해시 저장
+-- (계정 연결은 교환 단계가 없으므로 NULL, nonce 가 없는 교환 코드는 거부)
+ALTER TABLE oauth_states ADD COLUMN nonce_hash CHAR(64) NULL;
+ALTER TABLE oauth_handoffs ADD COLUMN nonce_hash CHAR(64) NULL;